@@ -0,0 +1,62 @@
+package satellite
+
+import (
+	"time"
+)
+
+// SatNum returns the satellite catalog number
+func (sat *Satellite) SatNum() int64 {
+	return sat.satnum
+}
+
+// Epoch returns the epoch of the element set in UTC
+func (sat *Satellite) Epoch() time.Time {
+	start := time.Date(expandEpochYear(sat.epochyr), time.January, 1, 0, 0, 0, 0, time.UTC)
+	return start.Add(time.Duration((sat.epochdays - 1) * 86400 * float64(time.Second))).Round(time.Microsecond)
+}
+
+// Inclination returns the mean inclination in degrees
+func (sat *Satellite) Inclination() float64 {
+	return sat.elementDeg(sat.inclo)
+}
+
+// RAAN returns the mean right ascension of the ascending node at epoch in degrees
+func (sat *Satellite) RAAN() float64 {
+	return sat.elementDeg(sat.nodeo)
+}
+
+// Eccentricity returns the mean eccentricity
+func (sat *Satellite) Eccentricity() float64 {
+	return sat.ecco
+}
+
+// ArgPerigee returns the mean argument of perigee at epoch in degrees
+func (sat *Satellite) ArgPerigee() float64 {
+	return sat.elementDeg(sat.argpo)
+}
+
+// MeanAnomaly returns the mean anomaly at epoch in degrees
+func (sat *Satellite) MeanAnomaly() float64 {
+	return sat.elementDeg(sat.mo)
+}
+
+// MeanMotion returns the mean motion in revolutions per day as given in the TLE
+func (sat *Satellite) MeanMotion() float64 {
+	if sat.init == "" {
+		return sat.no
+	}
+	return sat.noKozai * XPDOTP
+}
+
+// Bstar returns the drag term in inverse Earth radii
+func (sat *Satellite) Bstar() float64 {
+	return sat.bstar
+}
+
+// Returns an angular element in degrees, which initialization converts to radians
+func (sat *Satellite) elementDeg(angle float64) float64 {
+	if sat.init == "" {
+		return angle
+	}
+	return angle * RAD2DEG
+}
@@ -0,0 +1,41 @@
+package satellite
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("accessors", func() {
+	line1 := "1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927"
+	line2 := "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537"
+
+	It("should read back the TLE elements in TLE units whether or not the satellite is initialized", func() {
+		parsed := ParseTLE(line1, line2, "wgs72")
+		initialized := TLEToSat(line1, line2, "wgs72")
+		for _, sat := range []*Satellite{&parsed, &initialized} {
+			Expect(sat.SatNum()).To(Equal(int64(25544)))
+			Expect(sat.Inclination()).To(BeNumerically("~", 51.6416, 1e-12))
+			Expect(sat.RAAN()).To(BeNumerically("~", 247.4627, 1e-12))
+			Expect(sat.Eccentricity()).To(BeNumerically("~", 0.0006703, 1e-15))
+			Expect(sat.ArgPerigee()).To(BeNumerically("~", 130.5360, 1e-12))
+			Expect(sat.MeanAnomaly()).To(BeNumerically("~", 325.0288, 1e-12))
+			Expect(sat.MeanMotion()).To(BeNumerically("~", 15.72125391, 1e-12))
+			Expect(sat.Bstar()).To(BeNumerically("~", -0.11606e-4, 1e-15))
+		}
+	})
+
+	It("should return the epoch with its fractional seconds", func() {
+		sat := TLEToSat(line1, line2, "wgs72")
+		want := time.Date(2008, 9, 20, 12, 25, 40, 104192000, time.UTC)
+		Expect(sat.Epoch().Sub(want)).To(BeNumerically("~", 0, time.Millisecond))
+
+		old := TLEToSat("1 00005U 58002B   00179.78495062  .00000023  00000-0  28098-4 0  4753", "2 00005  34.2682 348.7242 1859667 331.7664  19.3264 10.82419157413667", "wgs72")
+		Expect(old.Epoch().Year()).To(Equal(2000))
+		Expect(old.Epoch().YearDay()).To(Equal(179))
+
+		older := ParseTLE("1 88888U          80275.98708465  .00073094  13844-3  66816-4 0    8", "2 88888  72.8435 115.9689 0086731  52.6988 110.5714 16.05824518  105", "wgs72")
+		Expect(older.Epoch().Year()).To(Equal(1980))
+	})
+})
@@ -0,0 +1,59 @@
+package satellite
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var ErrInvalidCatalogNumber = errors.New("invalid satellite catalog number")
+
+// Leading characters of Alpha-5 catalog numbers in order of value, starting at 10. I and O are skipped
+// as they read like 1 and 0.
+const alpha5Letters = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+
+// Largest catalog number Alpha-5 can represent, Z9999
+const maxAlpha5 = (10+int64(len(alpha5Letters)))*10000 - 1
+
+// DecodeAlpha5 converts a satellite catalog number as written in columns 3-7 of a TLE into an integer.
+// Numbers of 100000 and more use the Alpha-5 scheme, where a leading letter stands for its value times
+// 10000 (A=10 up to Z=33, skipping I and O), so T5678 decodes to 275678. Plain numbers are unchanged.
+func DecodeAlpha5(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, errors.Wrap(ErrInvalidCatalogNumber, "empty")
+	}
+
+	if c := s[0]; c >= 'A' && c <= 'Z' {
+		lead := strings.IndexByte(alpha5Letters, c)
+		rest := s[1:]
+		if lead < 0 || len(rest) != 4 {
+			return 0, errors.Wrapf(ErrInvalidCatalogNumber, "%q", s)
+		}
+		n, err := strconv.ParseUint(rest, 10, 0)
+		if err != nil {
+			return 0, errors.Wrapf(ErrInvalidCatalogNumber, "%q", s)
+		}
+		return int64(10+lead)*10000 + int64(n), nil
+	}
+
+	n, err := strconv.ParseInt(s, 10, 0)
+	if err != nil || n < 0 {
+		return 0, errors.Wrapf(ErrInvalidCatalogNumber, "%q", s)
+	}
+	return n, nil
+}
+
+// EncodeAlpha5 formats a satellite catalog number for columns 3-7 of a TLE, zero padded below 100000
+// and in the Alpha-5 scheme described by DecodeAlpha5 from there up to 339999
+func EncodeAlpha5(n int64) (string, error) {
+	if n < 0 || n > maxAlpha5 {
+		return "", errors.Wrapf(ErrInvalidCatalogNumber, "%d out of range", n)
+	}
+	if n < 100000 {
+		return fmt.Sprintf("%05d", n), nil
+	}
+	return fmt.Sprintf("%c%04d", alpha5Letters[n/10000-10], n%10000), nil
+}
@@ -0,0 +1,49 @@
+package satellite
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/pkg/errors"
+)
+
+var _ = Describe("Alpha-5", func() {
+	It("should decode plain and Alpha-5 catalog numbers", func() {
+		for s, want := range map[string]int64{"25544": 25544, "04632": 4632, "  123": 123, "A0000": 100000, "H9999": 179999, "J0000": 180000, "T5678": 275678, "Z9999": 339999} {
+			n, err := DecodeAlpha5(s)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n).To(Equal(want), s)
+		}
+	})
+
+	It("should reject the letters I and O and malformed numbers", func() {
+		for _, s := range []string{"I1234", "O1234", "a1234", "T56", "T56X8", "", "-1234"} {
+			_, err := DecodeAlpha5(s)
+			Expect(errors.Cause(err)).To(Equal(ErrInvalidCatalogNumber), s)
+		}
+	})
+
+	It("should round trip through EncodeAlpha5", func() {
+		for _, n := range []int64{0, 4632, 99999, 100000, 180000, 275678, 339999} {
+			s, err := EncodeAlpha5(n)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).To(HaveLen(5))
+			decoded, err := DecodeAlpha5(s)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decoded).To(Equal(n))
+		}
+		Expect(EncodeAlpha5(4632)).To(Equal("04632"))
+
+		_, err := EncodeAlpha5(340000)
+		Expect(errors.Cause(err)).To(Equal(ErrInvalidCatalogNumber))
+	})
+
+	It("should parse an Alpha-5 satellite number from a TLE", func() {
+		sat, err := ParseTLEV2("1 T5678U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 T5678  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sat.satnum).To(Equal(int64(275678)))
+
+		_, err = ParseTLEV2("1 I5678U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 I5678  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+		Expect(errors.Cause(err)).To(Equal(ErrInvalidTLE))
+	})
+})
@@ -0,0 +1,101 @@
+package satellite
+
+import (
+	"fmt"
+	"time"
+)
+
+// StaleEpochAge is the epoch age beyond which CatalogHealth reports an element set as stale, two weeks
+// by default. Most tracked objects are published at least that often, so an older element set has
+// usually been lost or superseded; LEO predictions are off by kilometres well before then, and a
+// tracking service may want a shorter limit. It may be changed to suit an application, before any
+// goroutine calls CatalogHealth.
+var StaleEpochAge = 14 * 24 * time.Hour
+
+// Altitude in km below which a satellite is taken to have reentered
+const decayAltitudeKm = 100.0
+
+// EpochAge returns how long before now the element set's epoch was, negative for an epoch in the future
+func (sat *Satellite) EpochAge(now time.Time) time.Duration {
+	return now.Sub(sat.Epoch())
+}
+
+// LikelyDecayed reports whether propagating the satellite to now fails, as SGP4 does once drag has
+// brought the orbit down, or puts it below 100 km. It ignores MaxPropagationSpan, as the question is
+// often asked about old element sets, but SGP4 is unreliable that far out so the answer is only a guess.
+func (sat *Satellite) LikelyDecayed(now time.Time) bool {
+	s := *sat
+	pos, _ := sgp4(&s, minutesSinceEpoch(&s, now))
+	return s.Error != 0 || pos.norm()-s.whichconst.radiusearthkm < decayAltitudeKm
+}
+
+// CatalogIssueKind classifies a problem CatalogHealth found with a satellite
+type CatalogIssueKind string
+
+const (
+	IssueInitError   CatalogIssueKind = "init error"
+	IssueImplausible CatalogIssueKind = "implausible"
+	IssueDecayed     CatalogIssueKind = "decayed"
+	IssueStale       CatalogIssueKind = "stale"
+)
+
+// CatalogIssue is a problem found with one satellite of a catalog
+type CatalogIssue struct {
+	SatNum int64
+	Kind   CatalogIssueKind
+	Detail string
+}
+
+// CatalogReport summarizes the health of a catalog. A satellite may count under several problems, but
+// one that failed initialization isn't checked further.
+type CatalogReport struct {
+	Total, Healthy                          int
+	InitErrors, Implausible, Decayed, Stale int
+	Issues                                  []CatalogIssue // In catalog order
+}
+
+// CatalogHealth checks every satellite of a catalog at time now: whether sgp4init succeeded, whether
+// Validate accepts its elements, whether it has LikelyDecayed and whether its epoch is older than
+// StaleEpochAge. It returns the counts of each problem along with a list of the issues by catalog number.
+// A nil entry counts as an init error with a catalog number of 0.
+func CatalogHealth(sats []*Satellite, now time.Time) CatalogReport {
+	report := CatalogReport{Total: len(sats)}
+	add := func(satnum int64, kind CatalogIssueKind, detail string) {
+		report.Issues = append(report.Issues, CatalogIssue{SatNum: satnum, Kind: kind, Detail: detail})
+	}
+	for _, sat := range sats {
+		if sat == nil {
+			report.InitErrors++
+			add(0, IssueInitError, "nil satellite")
+			continue
+		}
+		issues := len(report.Issues)
+
+		if sat.init == "" || sat.Error != 0 {
+			report.InitErrors++
+			detail := sat.ErrorStr
+			if sat.init == "" {
+				detail = ErrNotInitialized.Error()
+			}
+			add(sat.satnum, IssueInitError, detail)
+			continue
+		}
+		if err := sat.Validate(); err != nil {
+			report.Implausible++
+			add(sat.satnum, IssueImplausible, err.Error())
+		}
+		if sat.LikelyDecayed(now) {
+			report.Decayed++
+			add(sat.satnum, IssueDecayed, fmt.Sprintf("no longer in orbit at %s", now.UTC().Format(time.RFC3339)))
+		}
+		if age := sat.EpochAge(now); age > StaleEpochAge {
+			report.Stale++
+			add(sat.satnum, IssueStale, fmt.Sprintf("epoch is %.1f days old", age.Hours()/24))
+		}
+
+		if len(report.Issues) == issues {
+			report.Healthy++
+		}
+	}
+	return report
+}
@@ -0,0 +1,57 @@
+package satellite
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("catalog health", func() {
+	iss := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+	draggy := TLEToSat("1 25545U 98067A   08264.51782528 -.00002182  00000-0  10000-1 0  2927", "2 25545  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+	distant := TLEToSat("1 25546U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25546  51.6416 247.4627 0006703 130.5360 325.0288  0.00500000563537", "wgs72")
+	epoch := time.Date(2008, 9, 20, 12, 25, 40, 0, time.UTC)
+
+	It("should report the epoch age and decay of single satellites", func() {
+		Expect(iss.EpochAge(epoch.Add(48 * time.Hour))).To(BeNumerically("~", 48*time.Hour, time.Second))
+		Expect(iss.LikelyDecayed(epoch.Add(48 * time.Hour))).To(BeFalse())
+		Expect(draggy.LikelyDecayed(epoch.Add(20 * 24 * time.Hour))).To(BeTrue())
+	})
+
+	It("should count each problem and list the issues by catalog number", func() {
+		report := CatalogHealth([]*Satellite{&iss, &draggy, &distant, {}}, epoch.Add(20*24*time.Hour))
+		Expect(report.Total).To(Equal(4))
+		Expect(report.InitErrors).To(Equal(1))
+		Expect(report.Implausible).To(Equal(1))
+		Expect(report.Decayed).To(BeNumerically(">=", 1))
+		Expect(report.Stale).To(Equal(3))
+		Expect(report.Healthy).To(BeZero())
+
+		var draggyKinds []CatalogIssueKind
+		for _, issue := range report.Issues {
+			if issue.SatNum == 25545 {
+				draggyKinds = append(draggyKinds, issue.Kind)
+			}
+		}
+		Expect(draggyKinds).To(Equal([]CatalogIssueKind{IssueDecayed, IssueStale}))
+	})
+
+	It("should report nil entries instead of panicking", func() {
+		report := CatalogHealth([]*Satellite{nil, &iss, nil}, epoch.Add(24*time.Hour))
+		Expect(report.Total).To(Equal(3))
+		Expect(report.InitErrors).To(Equal(2))
+		Expect(report.Healthy).To(Equal(1))
+		Expect(report.Issues).To(HaveLen(2))
+		for _, issue := range report.Issues {
+			Expect(issue.SatNum).To(BeZero())
+			Expect(issue.Kind).To(Equal(IssueInitError))
+		}
+	})
+
+	It("should count a fresh element set as healthy", func() {
+		report := CatalogHealth([]*Satellite{&iss}, epoch.Add(24*time.Hour))
+		Expect(report.Healthy).To(Equal(1))
+		Expect(report.Issues).To(BeEmpty())
+	})
+})
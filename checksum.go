@@ -0,0 +1,57 @@
+package satellite
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var ErrBadChecksum = errors.New("TLE checksum mismatch")
+
+// Column holding the modulo 10 checksum of a TLE line
+const tleChecksumColumn = tleMaxLineLength - 1
+
+// VerifyChecksum reports whether the checksum in column 69 of a TLE line matches columns 1-68, in which
+// digits count their value, minus signs 1 and everything else 0. Trailing whitespace is ignored. It
+// returns an error wrapping ErrInvalidTLE if the line has no checksum column or it isn't a digit.
+func VerifyChecksum(line string) (bool, error) {
+	line = strings.TrimRight(line, " \t\r\n")
+	if len(line) != tleMaxLineLength {
+		return false, errors.Wrapf(ErrInvalidTLE, "line is %d characters long, want %d with a checksum", len(line), tleMaxLineLength)
+	}
+	want := line[tleChecksumColumn]
+	if want < '0' || want > '9' {
+		return false, errors.Wrapf(ErrInvalidTLE, "checksum %q is not a digit", want)
+	}
+	return tleChecksum(line[:tleChecksumColumn]) == int(want-'0'), nil
+}
+
+// Returns the modulo 10 checksum of the given TLE columns
+func tleChecksum(columns string) int {
+	sum := 0
+	for _, c := range columns {
+		switch {
+		case c >= '0' && c <= '9':
+			sum += int(c - '0')
+		case c == '-':
+			sum++
+		}
+	}
+	return sum % 10
+}
+
+// Parses a two line element dataset like ParseTLEV2, additionally verifying the checksum of both lines.
+// A mismatch returns an error wrapping ErrBadChecksum that names the line, so a transmission error is
+// caught instead of silently producing a wrong orbit.
+func ParseTLEV2WithValidation(line1, line2 string, gravConst Gravity) (Satellite, error) {
+	for i, line := range []string{line1, line2} {
+		ok, err := VerifyChecksum(line)
+		if err != nil {
+			return Satellite{}, errors.Wrapf(err, "line %d", i+1)
+		}
+		if !ok {
+			return Satellite{}, errors.Wrapf(ErrBadChecksum, "line %d", i+1)
+		}
+	}
+	return ParseTLEV2(line1, line2, gravConst)
+}
@@ -0,0 +1,49 @@
+package satellite
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/pkg/errors"
+)
+
+var _ = Describe("VerifyChecksum", func() {
+	line1 := "1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927"
+	line2 := "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537"
+
+	It("should accept lines with matching checksums", func() {
+		for _, line := range []string{line1, line2, line2 + " \r\n"} {
+			ok, err := VerifyChecksum(line)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+		}
+	})
+
+	It("should detect a single character error", func() {
+		ok, err := VerifyChecksum(line2[:60] + "4" + line2[61:])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should reject a missing or non-digit checksum", func() {
+		_, err := VerifyChecksum(line1[:68])
+		Expect(errors.Cause(err)).To(Equal(ErrInvalidTLE))
+
+		_, err = VerifyChecksum(line1[:68] + "X")
+		Expect(errors.Cause(err)).To(Equal(ErrInvalidTLE))
+	})
+
+	Describe("ParseTLEV2WithValidation", func() {
+		It("should match ParseTLEV2 for valid lines", func() {
+			sat, err := ParseTLEV2WithValidation(line1, line2, "wgs72")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sat).To(Equal(ParseTLE(line1, line2, "wgs72")))
+		})
+
+		It("should name the line whose checksum fails", func() {
+			_, err := ParseTLEV2WithValidation(line1, line2[:68]+"8", "wgs72")
+			Expect(errors.Cause(err)).To(Equal(ErrBadChecksum))
+			Expect(err.Error()).To(ContainSubstring("line 2"))
+		})
+	})
+})
@@ -1,8 +1,8 @@
 package satellite
 
 import (
-	"log"
 	"math"
+	"time"
 )
 
 // this procedure converts the day of the year, epochDays, to the equivalent month day, hour, minute and second.
@@ -43,9 +43,9 @@ func JDay(year, mon, day, hr, min, sec int) float64 {
 	return (367.0*float64(year) - math.Floor((7*(float64(year)+math.Floor((float64(mon)+9)/12.0)))*0.25) + math.Floor(275*float64(mon)/9.0) + float64(day) + 1721013.5 + ((float64(sec)/60.0+float64(min))/60.0+float64(hr))/24.0)
 }
 
-// this function finds the greenwich sidereal time (iau-82)
-func gstime(jdut1 float64) (temp float64) {
-	tut1 := (jdut1 - 2451545.0) / 36525.0
+// this function finds the greenwich sidereal time (iau-82) for a UTC julian date, converting it to UT1 with UT1MinusUTC
+func gstime(jdutc float64) (temp float64) {
+	tut1 := (ut1FromUTC(jdutc) - 2451545.0) / 36525.0
 	temp = -6.2e-6*tut1*tut1*tut1 + 0.093104*tut1*tut1 + (876600.0*3600+8640184.812866)*tut1 + 67310.54841
 	temp = math.Mod((temp * DEG2RAD / 240.0), TWOPI)
 
@@ -56,13 +56,64 @@ func gstime(jdut1 float64) (temp float64) {
 	return
 }
 
-// Calc GST given year, month, day, hour, minute and second
+// Calc GST given year, month, day, hour, minute and second in UTC
 func GSTimeFromDate(year, mon, day, hr, min, sec int) float64 {
 	jDay := JDay(year, mon, day, hr, min, sec)
 	return gstime(jDay)
 }
 
+// GMSTFromTime returns the Greenwich mean sidereal time (IAU-82) in radians [0, 2pi) at time t, which is
+// interpreted as UTC whatever its location and keeps its fractional seconds. Sidereal time follows UT1,
+// so t is converted with UT1MinusUTC; leaving that at zero treats UTC as UT1, an error of under a second
+// of time. This is the angle the package rotates TEME coordinates by, so use it for your own ECI to ECEF
+// rotations to agree with ECIToLLA and the look angle functions.
+func GMSTFromTime(t time.Time) float64 {
+	return gstime(jdayFromTime(t))
+}
+
+// Calculates the equation of the equinoxes in radians, the nutation in longitude projected onto the
+// equator, from the four largest nutation terms. Good to about 0.01 s of time.
+// Reference: Meeus, Astronomical Algorithms, chapter 22.
+func equationOfEquinoxes(jdutc float64) float64 {
+	t := (jdutc - 2451545.0) / 36525.0
+	node := (125.04452 - 1934.136261*t) * DEG2RAD
+	sunLong := (280.4665 + 36000.7698*t) * DEG2RAD
+	moonLong := (218.3165 + 481267.8813*t) * DEG2RAD
+
+	dpsi := (-17.20*math.Sin(node) - 1.32*math.Sin(2*sunLong) - 0.23*math.Sin(2*moonLong) + 0.21*math.Sin(2*node)) / 3600 * DEG2RAD
+	obliquity := (23.439291 - 0.0130042*t) * DEG2RAD
+	return dpsi * math.Cos(obliquity)
+}
+
+// Calculates Greenwich apparent sidereal time in radians [0, 2pi) for a UTC julian date
+func gast(jdutc float64) float64 {
+	ret := math.Mod(gstime(jdutc)+equationOfEquinoxes(jdutc), TWOPI)
+	if ret < 0 {
+		ret += TWOPI
+	}
+	return ret
+}
+
+// GASTFromTime returns the Greenwich apparent sidereal time in radians [0, 2pi) at time t: GMSTFromTime
+// plus the equation of the equinoxes, the nutation in longitude times the cosine of the obliquity, which
+// stays within about a second of time. Like GMSTFromTime it converts t to UT1 with UT1MinusUTC.
+func GASTFromTime(t time.Time) float64 {
+	return gast(jdayFromTime(t))
+}
+
+// LASTFromTime returns the local apparent sidereal time in radians [0, 2pi) at longitudeDeg (east positive)
+// at time t. It equals the right ascension crossing the local meridian, so the hour angle of an object is
+// LAST minus its right ascension. Like the Greenwich sidereal time it builds on, it uses UT1MinusUTC.
+func LASTFromTime(t time.Time, longitudeDeg float64) float64 {
+	ret := math.Mod(gast(jdayFromTime(t))+longitudeDeg*DEG2RAD, TWOPI)
+	if ret < 0 {
+		ret += TWOPI
+	}
+	return ret
+}
+
 // Convert Earth Centered Inertial coordinated into equivalent latitude, longitude, altitude and velocity.
+// The geodetic latitude is in [-pi/2, pi/2] and the longitude in (-pi, pi] radians.
 // Reference: http://celestrak.com/columns/v02n03/
 func ECIToLLA(eciCoords Vector3, gmst float64) (altitude, velocity float64, ret LatLong) {
 	a := 6378.137     // Semi-major Axis
@@ -90,7 +141,7 @@ func ECIToLLA(eciCoords Vector3, gmst float64) (altitude, velocity float64, ret
 	velocity = math.Sqrt(398600.4418 / (altitude + 6378.137))
 
 	ret.Latitude = latitude
-	ret.Longitude = longitude
+	ret.Longitude = wrapPi(longitude)
 
 	return
 }
@@ -99,21 +150,22 @@ func ECIToLLA(eciCoords Vector3, gmst float64) (altitude, velocity float64, ret
 func LatLongDeg(rad LatLong) (deg LatLong) {
 	deg.Longitude = math.Mod(rad.Longitude/math.Pi*180, 360)
 	if deg.Longitude > 180 {
-		deg.Longitude = 360 - deg.Longitude
-	} else if deg.Longitude < -180 {
+		deg.Longitude = deg.Longitude - 360
+	} else if deg.Longitude <= -180 {
 		deg.Longitude = 360 + deg.Longitude
 	}
 
 	if rad.Latitude < (-math.Pi/2) || rad.Latitude > math.Pi/2 {
-		log.Fatal("Latitude not within bounds -pi/2 to +pi/2")
+		logf("latitude %v not within bounds -pi/2 to +pi/2", rad.Latitude)
 	}
 	deg.Latitude = (rad.Latitude / math.Pi * 180)
 	return
 }
 
-// Calculate GMST from Julian date.
+// Calculate GMST from a UTC Julian date, converting it to UT1 with UT1MinusUTC.
 // Reference: The 1992 Astronomical Almanac, page B6.
 func ThetaG_JD(jday float64) (ret float64) {
+	jday = ut1FromUTC(jday)
 	_, UT := math.Modf(jday + 0.5)
 	jday = jday - UT
 	TU := (jday - 2451545.0) / 36525.0
@@ -135,7 +187,9 @@ func LLAToECI(obsCoords LatLong, alt, jday float64) (eciObs Vector3) {
 	return
 }
 
-// Convert Earth Centered Intertial coordinates into Earth Cenetered Earth Final coordinates
+// Convert Earth Centered Intertial coordinates into Earth Cenetered Earth Final coordinates.
+// The input is in the TEME frame SGP4 propagates in and is rotated by the sidereal time gmst in radians
+// about the pole, which gives Earth fixed coordinates to within the tens of metres of polar motion.
 // Reference: http://ccar.colorado.edu/ASEN5070/handouts/coordsys.doc
 func ECIToECEF(eciCoords Vector3, gmst float64) (ecfCoords Vector3) {
 	ecfCoords.X = eciCoords.X*math.Cos(gmst) + eciCoords.Y*math.Sin(gmst)
@@ -144,12 +198,42 @@ func ECIToECEF(eciCoords Vector3, gmst float64) (ecfCoords Vector3) {
 	return
 }
 
-// Calculate look angles for given satellite position and observer position
+// ECEFToECI is the inverse of ECIToECEF, rotating Earth fixed coordinates back into the TEME frame of
+// SGP4 by the sidereal time gmst in radians
+func ECEFToECI(ecfCoords Vector3, gmst float64) (eciCoords Vector3) {
+	eciCoords.X = ecfCoords.X*math.Cos(gmst) - ecfCoords.Y*math.Sin(gmst)
+	eciCoords.Y = ecfCoords.X*math.Sin(gmst) + ecfCoords.Y*math.Cos(gmst)
+	eciCoords.Z = ecfCoords.Z
+	return
+}
+
+// LLAToECEF converts a geodetic latitude and longitude in radians and altitude in km into Earth fixed
+// coordinates(km) on the WGS84 ellipsoid that ECIToLLA uses, so that it inverts ECIToLLA
+func LLAToECEF(coords LatLong, alt float64) (ecfCoords Vector3) {
+	a := 6378.137     // Semi-major Axis
+	b := 6356.7523142 // Semi-minor Axis
+	e2 := 1 - (b*b)/(a*a)
+
+	sinLat := math.Sin(coords.Latitude)
+	n := a / math.Sqrt(1-e2*sinLat*sinLat) // Prime vertical radius of curvature
+	ecfCoords.X = (n + alt) * math.Cos(coords.Latitude) * math.Cos(coords.Longitude)
+	ecfCoords.Y = (n + alt) * math.Cos(coords.Latitude) * math.Sin(coords.Longitude)
+	ecfCoords.Z = (n*(1-e2) + alt) * sinLat
+	return
+}
+
+// Calculate look angles for given satellite position and observer position, with the azimuth in [0, 2pi)
+// measured clockwise from north and the elevation in [-pi/2, pi/2].
 // obsAlt in km
 // Reference: http://celestrak.com/columns/v02n02/
 func ECIToLookAngles(eciSat Vector3, obsCoords LatLong, obsAlt, jday float64) (lookAngles LookAngles) {
+	return topocentricLookAngles(eciSat, LLAToECI(obsCoords, obsAlt, jday), obsCoords, jday)
+}
+
+// Returns the look angles of a satellite at eciSat from an observer at obsPos in ECI(km), whose local
+// vertical is given by its geodetic coordinates obsCoords
+func topocentricLookAngles(eciSat, obsPos Vector3, obsCoords LatLong, jday float64) (lookAngles LookAngles) {
 	theta := math.Mod(ThetaG_JD(jday)+obsCoords.Longitude, 2*math.Pi)
-	obsPos := LLAToECI(obsCoords, obsAlt, jday)
 
 	rx := eciSat.X - obsPos.X
 	ry := eciSat.Y - obsPos.Y
@@ -159,15 +243,84 @@ func ECIToLookAngles(eciSat Vector3, obsCoords LatLong, obsAlt, jday float64) (l
 	top_e := -math.Sin(theta)*rx + math.Cos(theta)*ry
 	top_z := math.Cos(obsCoords.Latitude)*math.Cos(theta)*rx + math.Cos(obsCoords.Latitude)*math.Sin(theta)*ry + math.Sin(obsCoords.Latitude)*rz
 
-	lookAngles.Az = math.Atan(-top_e / top_s)
-	if top_s > 0 {
-		lookAngles.Az = lookAngles.Az + math.Pi
+	lookAngles.Az = wrapTwoPi(math.Atan2(top_e, -top_s))
+	lookAngles.Rg = math.Sqrt(rx*rx + ry*ry + rz*rz)
+	// Rounding can take the ratio just past ±1 straight overhead or underfoot
+	lookAngles.El = math.Asin(math.Max(-1, math.Min(1, top_z/lookAngles.Rg)))
+
+	return
+}
+
+// ECIToRaDec returns the topocentric right ascension in [0, 2pi) and declination in [-pi/2, pi/2] of a
+// satellite as seen from an observer, both given as ECI positions in km, by the direction of the vector
+// between them. Straight above or below a pole the right ascension is undefined and comes out as zero.
+func ECIToRaDec(satECI, observerECI Vector3) (ra, dec float64) {
+	rho := satECI.sub(observerECI)
+	ra = wrapTwoPi(math.Atan2(rho.Y, rho.X))
+	dec = math.Atan2(rho.Z, math.Hypot(rho.X, rho.Y))
+	return
+}
+
+// ECIToLookAnglesV2 is ECIToLookAngles with the range rate filled in from the satellite's ECI velocity
+// eciVel (km/s), relative to the observer carried around by the Earth's rotation. Unlike ECIToLookAngles
+// it places the observer obsAlt km above the WGS84 ellipsoid rather than on a sphere, as do the pass,
+// visibility and other Observer functions built on it.
+func ECIToLookAnglesV2(eciSat, eciVel Vector3, obsCoords LatLong, obsAlt, jday float64) LookAngles {
+	obs := Observer{Coords: obsCoords, Altitude: obsAlt}
+	obsPos := obs.eci(jday)
+	return lookAnglesWithRate(eciSat, eciVel, obsPos, obs.eciVelocity(obsPos, Vector3{}, jday), obsCoords, jday)
+}
+
+// Returns topocentricLookAngles with the range rate filled in from the satellite's velocity eciVel and
+// the observer's inertial velocity obsVel
+func lookAnglesWithRate(eciSat, eciVel, obsPos, obsVel Vector3, obsCoords LatLong, jday float64) LookAngles {
+	lookAngles := topocentricLookAngles(eciSat, obsPos, obsCoords, jday)
+	lookAngles.Rr = eciVel.sub(obsVel).dot(eciSat.sub(obsPos).unit())
+	return lookAngles
+}
+
+// Wraps an angle in radians into the range [0, 2pi)
+func wrapTwoPi(angle float64) float64 {
+	angle = math.Mod(angle, TWOPI)
+	if angle < 0 {
+		angle += TWOPI
 	}
-	if lookAngles.Az < 0 {
-		lookAngles.Az = lookAngles.Az + 2*math.Pi
+	// A tiny negative angle rounds up to 2pi when wrapped
+	if angle >= TWOPI {
+		angle = 0
 	}
-	lookAngles.Rg = math.Sqrt(rx*rx + ry*ry + rz*rz)
-	lookAngles.El = math.Asin(top_z / lookAngles.Rg)
+	return angle
+}
+
+// Wraps an angle in radians into the range (-pi, pi]
+func wrapPi(angle float64) float64 {
+	angle = math.Mod(angle, TWOPI)
+	if angle <= -math.Pi {
+		angle += TWOPI
+	} else if angle > math.Pi {
+		angle -= TWOPI
+	}
+	return angle
+}
+
+// Converts an ECI position (km) and velocity (km/s) into ECEF, removing the Earth's rotation from the velocity
+func eciToECEFState(pos, vel Vector3, gmst float64) (ecefPos, ecefVel Vector3) {
+	ecefPos = ECIToECEF(pos, gmst)
+	rotated := ECIToECEF(vel, gmst)
+	ecefVel = Vector3{
+		X: rotated.X + earthRotationRate*ecefPos.Y,
+		Y: rotated.Y - earthRotationRate*ecefPos.X,
+		Z: rotated.Z,
+	}
+	return
+}
 
+// Rotates an ECEF vector into east, north and up components at the given latitude and longitude in radians
+func ecefToENU(v Vector3, ll LatLong) (east, north, up float64) {
+	sinLat, cosLat := math.Sin(ll.Latitude), math.Cos(ll.Latitude)
+	sinLon, cosLon := math.Sin(ll.Longitude), math.Cos(ll.Longitude)
+	east = -sinLon*v.X + cosLon*v.Y
+	north = -sinLat*cosLon*v.X - sinLat*sinLon*v.Y + cosLat*v.Z
+	up = cosLat*cosLon*v.X + cosLat*sinLon*v.Y + sinLat*v.Z
 	return
 }
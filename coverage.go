@@ -0,0 +1,166 @@
+package satellite
+
+import (
+	"math"
+	"time"
+)
+
+// RevisitStats returns the mean and longest gaps between successive accesses to target (geodetic
+// latitude and longitude in radians, on the WGS84 ellipsoid) by any satellite in sats between start
+// and end, along with the number of accesses. An access lasts while at least one satellite is at or
+// above minElevationDeg as seen from target, so overlapping passes by different satellites count once.
+// Time before the first and after the last access isn't counted as a gap; fewer than two accesses give
+// zero gaps. The elevations are sampled every step, which must be shorter than the briefest pass of
+// interest.
+func RevisitStats(sats []*Satellite, target LatLong, minElevationDeg float64, start, end time.Time, step time.Duration) (meanGap, maxGap time.Duration, count int, err error) {
+	obs := Observer{Coords: target}
+	minEl := minElevationDeg * DEG2RAD
+	initial, transitions, err := findTransitions(start, end, step, func(t time.Time) (bool, error) {
+		for _, sat := range sats {
+			look, err := obs.lookAngles(*sat, t)
+			if err != nil {
+				return false, err
+			}
+			if look.El >= minEl {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	if initial {
+		count++
+	}
+	var total time.Duration
+	var gaps int
+	var lastLOS time.Time
+	for _, tr := range transitions {
+		if !tr.rising {
+			lastLOS = tr.t
+			continue
+		}
+		count++
+		if !lastLOS.IsZero() {
+			gap := tr.t.Sub(lastLOS)
+			total += gap
+			gaps++
+			if gap > maxGap {
+				maxGap = gap
+			}
+		}
+	}
+
+	if gaps > 0 {
+		meanGap = total / time.Duration(gaps)
+	}
+	return meanGap, maxGap, count, nil
+}
+
+// HorizonPoint is a vertex of a HorizonMask, with azimuth and elevation in radians
+type HorizonPoint struct {
+	Az, El float64
+}
+
+// HorizonMask describes the local horizon profile of a site, such as terrain or buildings blocking
+// the sky, as points sorted by azimuth in [0, 2π). The elevation between points is interpolated
+// linearly in azimuth, wrapping through north from the last point to the first. An empty mask is the
+// geometric horizon.
+type HorizonMask []HorizonPoint
+
+// ElevationAt returns the masked elevation in radians at azimuth az in radians
+func (m HorizonMask) ElevationAt(az float64) float64 {
+	if len(m) == 0 {
+		return 0
+	}
+	az = math.Mod(az, TWOPI)
+	if az < 0 {
+		az += TWOPI
+	}
+
+	// Find the points either side of az, wrapping around north
+	i := 0
+	for i < len(m) && m[i].Az <= az {
+		i++
+	}
+	prev, next := m[(i-1+len(m))%len(m)], m[i%len(m)]
+	span := next.Az - prev.Az
+	offset := az - prev.Az
+	if span <= 0 {
+		span += TWOPI
+	}
+	if offset < 0 {
+		offset += TWOPI
+	}
+	if span == 0 || span >= TWOPI {
+		return prev.El
+	}
+	return prev.El + (next.El-prev.El)*offset/span
+}
+
+// Returns whether the satellite is above target's horizon mask at time t
+func (m HorizonMask) visible(sat Satellite, target Observer, t time.Time) (bool, error) {
+	look, err := target.lookAngles(sat, t)
+	if err != nil {
+		return false, err
+	}
+	return look.El > m.ElevationAt(look.Az), nil
+}
+
+// TargetEverVisible reports whether the satellite rises above the horizon mask of target at any sample
+// taken every step between start and end inclusive, with target on the WGS84 ellipsoid
+func TargetEverVisible(sat Satellite, target Observer, mask HorizonMask, start, end time.Time, step time.Duration) (bool, error) {
+	times, err := sampleTimes(start, end, step)
+	if err != nil {
+		return false, err
+	}
+	for _, t := range times {
+		v, err := mask.visible(sat, target, t)
+		if err != nil || v {
+			return v, err
+		}
+	}
+	return false, nil
+}
+
+// TargetAccessDuration returns the total time between start and end that the satellite is above the
+// horizon mask of target. The elevation is sampled every step and each rise and set refined by bisection
+// to within a millisecond.
+func TargetAccessDuration(sat Satellite, target Observer, mask HorizonMask, start, end time.Time, step time.Duration) (time.Duration, error) {
+	initial, transitions, err := findTransitions(start, end, step, func(t time.Time) (bool, error) {
+		return mask.visible(sat, target, t)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var total time.Duration
+	for _, in := range transitionIntervals(start, end, initial, transitions) {
+		total += in.end.Sub(in.start)
+	}
+	return total, nil
+}
+
+// Sampling step used for TargetCoverageFraction, short enough not to miss a low pass
+const coverageStep = 30 * time.Second
+
+// TargetCoverageFraction returns the fraction of one repeat cycle of repeatDays days, starting at the
+// satellite's epoch, during which it is above minElevationDeg as seen from target (latitude and
+// longitude in radians, at sea level). For a repeat ground track orbit this is the long run coverage of
+// the target, as the same passes recur every cycle.
+func TargetCoverageFraction(sat Satellite, target LatLong, minElevationDeg float64, repeatDays int) (float64, error) {
+	if repeatDays <= 0 {
+		return 0, ErrInvalidRepeatCycle
+	}
+	start := timeFromJday(sat.jdsatepoch)
+	cycle := time.Duration(repeatDays) * 24 * time.Hour
+	mask := HorizonMask{{El: minElevationDeg * DEG2RAD}}
+
+	access, err := TargetAccessDuration(sat, Observer{Coords: target}, mask, start, start.Add(cycle), coverageStep)
+	if err != nil {
+		return 0, err
+	}
+	return access.Seconds() / cycle.Seconds(), nil
+}
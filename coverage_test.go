@@ -0,0 +1,133 @@
+package satellite
+
+import (
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("coverage", func() {
+	iss := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+	epoch := time.Date(2008, 9, 20, 12, 25, 40, 0, time.UTC)
+
+	Describe("RevisitStats", func() {
+		target := LatLong{Latitude: 45 * DEG2RAD, Longitude: 10 * DEG2RAD}
+
+		It("should report gaps between ISS passes over a mid latitude site", func() {
+			mean, max, count, err := RevisitStats([]*Satellite{&iss}, target, 10, epoch, epoch.Add(24*time.Hour), 30*time.Second)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(BeNumerically(">=", 2))
+			Expect(mean).To(BeNumerically(">", 80*time.Minute))
+			Expect(max).To(BeNumerically(">=", mean))
+		})
+
+		It("should find the gaps between the passes PredictPasses gives", func() {
+			north := LatLong{Latitude: 52 * DEG2RAD, Longitude: 5 * DEG2RAD}
+			passes, err := PredictPasses(&iss, north, 0, epoch, epoch.Add(24*time.Hour), 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(passes)).To(BeNumerically(">=", 3))
+
+			var total, longest time.Duration
+			for i := 1; i < len(passes); i++ {
+				gap := passes[i].AOS.Sub(passes[i-1].LOS)
+				total += gap
+				if gap > longest {
+					longest = gap
+				}
+			}
+
+			mean, max, count, err := RevisitStats([]*Satellite{&iss}, north, 10, epoch, epoch.Add(24*time.Hour), 20*time.Second)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(len(passes)))
+			Expect(mean).To(BeNumerically("~", total/time.Duration(len(passes)-1), 5*time.Millisecond))
+			Expect(max).To(BeNumerically("~", longest, 5*time.Millisecond))
+		})
+
+		It("should not shorten gaps by adding the same satellite twice", func() {
+			mean, max, count, err := RevisitStats([]*Satellite{&iss}, target, 10, epoch, epoch.Add(24*time.Hour), 30*time.Second)
+			Expect(err).NotTo(HaveOccurred())
+			mean2, max2, count2, err := RevisitStats([]*Satellite{&iss, &iss}, target, 10, epoch, epoch.Add(24*time.Hour), 30*time.Second)
+			Expect(err).NotTo(HaveOccurred())
+			Expect([]interface{}{mean2, max2, count2}).To(Equal([]interface{}{mean, max, count}))
+		})
+	})
+	Describe("HorizonMask", func() {
+		mask := HorizonMask{{Az: 0, El: 10 * DEG2RAD}, {Az: math.Pi / 2, El: 30 * DEG2RAD}, {Az: math.Pi, El: 10 * DEG2RAD}}
+
+		It("should interpolate between points and wrap through north", func() {
+			Expect(mask.ElevationAt(math.Pi / 4)).To(BeNumerically("~", 20*DEG2RAD, 1e-12))
+			Expect(mask.ElevationAt(math.Pi / 2)).To(BeNumerically("~", 30*DEG2RAD, 1e-12))
+			Expect(mask.ElevationAt(1.5 * math.Pi)).To(BeNumerically("~", 10*DEG2RAD, 1e-12))
+			Expect(mask.ElevationAt(-math.Pi / 4)).To(BeNumerically("~", 10*DEG2RAD, 1e-12))
+			Expect(HorizonMask{}.ElevationAt(1)).To(BeZero())
+			Expect(HorizonMask{{Az: 1, El: 0.1}}.ElevationAt(3)).To(Equal(0.1))
+		})
+	})
+
+	Describe("TargetAccessDuration", func() {
+		target := Observer{Coords: LatLong{Latitude: 45 * DEG2RAD, Longitude: 10 * DEG2RAD}}
+
+		It("should shrink as the target's horizon mask rises", func() {
+			open, err := TargetAccessDuration(iss, target, nil, epoch, epoch.Add(24*time.Hour), 30*time.Second)
+			Expect(err).NotTo(HaveOccurred())
+			masked, err := TargetAccessDuration(iss, target, HorizonMask{{Az: 0, El: 20 * DEG2RAD}}, epoch, epoch.Add(24*time.Hour), 30*time.Second)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(open).To(BeNumerically(">", 0))
+			Expect(masked).To(BeNumerically("<", open))
+		})
+
+		It("should compare the mask with the elevation seen from the target on the ellipsoid", func() {
+			north := Observer{Coords: LatLong{Latitude: 62 * DEG2RAD, Longitude: 25 * DEG2RAD}, Altitude: 0.2}
+			passes, err := PredictPasses(&iss, north.Coords, north.Altitude, epoch, epoch.Add(24*time.Hour), 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(passes).NotTo(BeEmpty())
+
+			top := passes[0].MaxElevationTime
+			look, err := ObserverLookAngles(&iss, north.Coords, north.Altitude, top)
+			Expect(err).NotTo(HaveOccurred())
+			below := HorizonMask{{Az: 0, El: look.El - 1e-6}}
+			above := HorizonMask{{Az: 0, El: look.El + 1e-6}}
+			Expect(TargetEverVisible(iss, north, below, top, top, time.Second)).To(BeTrue())
+			Expect(TargetEverVisible(iss, north, above, top, top, time.Second)).To(BeFalse())
+		})
+
+		It("should report a target that never sees the satellite behind a high mask", func() {
+			Expect(TargetEverVisible(iss, target, nil, epoch, epoch.Add(24*time.Hour), 30*time.Second)).To(BeTrue())
+			Expect(TargetEverVisible(iss, target, HorizonMask{{Az: 0, El: 89.9 * DEG2RAD}}, epoch, epoch.Add(24*time.Hour), 30*time.Second)).To(BeFalse())
+		})
+	})
+	Describe("TargetCoverageFraction", func() {
+		It("should be the access time over the cycle divided by its length", func() {
+			target := LatLong{Latitude: 45 * DEG2RAD, Longitude: 10 * DEG2RAD}
+			fraction, err := TargetCoverageFraction(iss, target, 10, 1)
+			Expect(err).NotTo(HaveOccurred())
+
+			access, err := TargetAccessDuration(iss, Observer{Coords: target}, HorizonMask{{El: 10 * DEG2RAD}}, epoch, epoch.Add(24*time.Hour), 30*time.Second)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fraction).To(BeNumerically("~", access.Hours()/24, 1e-4))
+			Expect(fraction).To(BeNumerically(">", 0))
+			Expect(fraction).To(BeNumerically("<", 0.05))
+		})
+
+		It("should cover repeat cycles longer than MaxPropagationSpan", func() {
+			target := LatLong{Latitude: 45 * DEG2RAD, Longitude: 10 * DEG2RAD}
+			fraction, err := TargetCoverageFraction(iss, target, 10, 35)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fraction).To(BeNumerically(">", 0))
+			Expect(fraction).To(BeNumerically("<", 0.05))
+		})
+
+		It("should be zero for a target the ground track never approaches", func() {
+			fraction, err := TargetCoverageFraction(iss, LatLong{Latitude: 89 * DEG2RAD}, 10, 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fraction).To(BeZero())
+		})
+
+		It("should reject a non-positive repeat cycle", func() {
+			_, err := TargetCoverageFraction(iss, LatLong{}, 10, 0)
+			Expect(err).To(Equal(ErrInvalidRepeatCycle))
+		})
+	})
+})
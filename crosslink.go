@@ -0,0 +1,81 @@
+package satellite
+
+import (
+	"math"
+	"time"
+)
+
+// LinkWindow is a span of time during which two satellites can communicate, with the shortest
+// distance in km between them during it
+type LinkWindow struct {
+	Start, End time.Time
+	MinRangeKm float64
+}
+
+// Reports whether the straight line between ECI positions p and q clears a sphere of the given radius
+// centered on the Earth
+func lineOfSight(p, q Vector3, radius float64) bool {
+	d := q.sub(p)
+	t := 0.0
+	if dd := d.dot(d); dd > 0 {
+		t = math.Max(0, math.Min(1, -p.dot(d)/dd))
+	}
+	return p.add(d.scale(t)).norm() > radius
+}
+
+// CrosslinkWindows returns the windows between start and end during which a and b are within maxRangeKm
+// of each other and the line between them clears the Earth, along with the closest approach during each.
+// A window under way at start begins at start, and one still under way at end finishes at end. The
+// geometry is sampled every step, window edges are refined by bisection to within a millisecond and each
+// minimum range by parabolic interpolation. The Earth is a sphere of the radius of a's gravity model,
+// without allowance for the atmosphere.
+func CrosslinkWindows(a, b Satellite, maxRangeKm float64, start, end time.Time, step time.Duration) ([]LinkWindow, error) {
+	geometry := func(t time.Time) (rangeKm float64, visible bool, err error) {
+		posA, _, err := propagateTime(a, t)
+		if err != nil {
+			return 0, false, err
+		}
+		posB, _, err := propagateTime(b, t)
+		if err != nil {
+			return 0, false, err
+		}
+		return posA.sub(posB).norm(), lineOfSight(posA, posB, a.whichconst.radiusearthkm), nil
+	}
+	rangeAt := func(t time.Time) (float64, error) {
+		r, _, err := geometry(t)
+		return r, err
+	}
+
+	initial, transitions, err := findTransitions(start, end, step, func(t time.Time) (bool, error) {
+		r, visible, err := geometry(t)
+		return visible && r <= maxRangeKm, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var windows []LinkWindow
+	for _, in := range transitionIntervals(start, end, initial, transitions) {
+		times, err := sampleTimes(in.start, in.end, step)
+		if err != nil {
+			return nil, err
+		}
+		best, bestRange := 0, math.Inf(1)
+		for i, t := range times {
+			r, err := rangeAt(t)
+			if err != nil {
+				return nil, err
+			}
+			if r < bestRange {
+				best, bestRange = i, r
+			}
+		}
+		if best > 0 && best < len(times)-1 {
+			if _, bestRange, err = refineMinimum(times[best-1], times[best], times[best+1], bestRange, rangeAt); err != nil {
+				return nil, err
+			}
+		}
+		windows = append(windows, LinkWindow{Start: in.start, End: in.end, MinRangeKm: bestRange})
+	}
+	return windows, nil
+}
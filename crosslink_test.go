@@ -0,0 +1,63 @@
+package satellite
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("crosslink", func() {
+	epoch := time.Date(2008, 9, 20, 12, 25, 40, 0, time.UTC)
+	iss := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+	// The same orbit half a revolution behind, so the Earth regularly blocks the link
+	opposite := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 145.0288 15.72125391563537", "wgs72")
+	// The same orbit ten degrees behind
+	trailing := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 315.0288 15.72125391563537", "wgs72")
+
+	Describe("lineOfSight", func() {
+		It("should be blocked through the Earth but clear above it", func() {
+			Expect(lineOfSight(Vector3{X: 7000}, Vector3{X: -7000}, 6378)).To(BeFalse())
+			Expect(lineOfSight(Vector3{X: 7000}, Vector3{Y: 7000}, 6378)).To(BeFalse())
+			Expect(lineOfSight(Vector3{X: 7000}, Vector3{X: 7000, Y: 1000}, 6378)).To(BeTrue())
+		})
+	})
+
+	Describe("CrosslinkWindows", func() {
+		It("should keep a link open the whole time to a satellite close behind", func() {
+			windows, err := CrosslinkWindows(iss, trailing, 2000, epoch, epoch.Add(3*time.Hour), time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(windows).To(HaveLen(1))
+			Expect(windows[0].Start).To(Equal(epoch))
+			Expect(windows[0].End).To(Equal(epoch.Add(3 * time.Hour)))
+			// Ten degrees of arc at 6730 km is about 1170 km
+			Expect(windows[0].MinRangeKm).To(BeNumerically("~", 1170, 50))
+		})
+
+		It("should never link satellites on opposite sides of the Earth", func() {
+			windows, err := CrosslinkWindows(iss, opposite, 20000, epoch, epoch.Add(3*time.Hour), time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(windows).To(BeEmpty())
+		})
+
+		It("should close the link when the range limit is exceeded", func() {
+			windows, err := CrosslinkWindows(iss, trailing, 1000, epoch, epoch.Add(3*time.Hour), time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(windows).To(BeEmpty())
+		})
+	})
+	Describe("CrosslinkWindows between planes", func() {
+		// Thirty degrees further east in node, so the two pass close by where the planes cross
+		crossing := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 277.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+
+		It("should open and close windows with the closest approach inside the range limit", func() {
+			windows, err := CrosslinkWindows(iss, crossing, 3000, epoch, epoch.Add(6*time.Hour), time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(windows)).To(BeNumerically(">=", 2))
+			for _, w := range windows {
+				Expect(w.End.After(w.Start)).To(BeTrue())
+				Expect(w.MinRangeKm).To(BeNumerically("<=", 3000))
+			}
+		})
+	})
+})
@@ -0,0 +1,79 @@
+package satellite
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Lagrange interpolation degree Cesium is told to use between CZML position samples
+const czmlInterpolationDegree = 5
+
+type czmlClock struct {
+	Interval    string `json:"interval"`
+	CurrentTime string `json:"currentTime"`
+}
+
+type czmlPosition struct {
+	Epoch                  string    `json:"epoch"`
+	ReferenceFrame         string    `json:"referenceFrame"`
+	InterpolationAlgorithm string    `json:"interpolationAlgorithm"`
+	InterpolationDegree    int       `json:"interpolationDegree"`
+	Cartesian              []float64 `json:"cartesian"`
+}
+
+type czmlPacket struct {
+	ID           string        `json:"id"`
+	Version      string        `json:"version,omitempty"`
+	Clock        *czmlClock    `json:"clock,omitempty"`
+	Availability string        `json:"availability,omitempty"`
+	Position     *czmlPosition `json:"position,omitempty"`
+}
+
+// WriteCZML writes a CZML document to w describing the paths of sats from start to end, sampled every
+// step, for display in CesiumJS. The document packet sets the clock to the interval, and each satellite
+// gets a packet identified by its catalog number with a position property holding the samples.
+//
+// Positions are Earth fixed (referenceFrame FIXED) cartesian coordinates in metres, rotated from the
+// propagator's TEME output by Greenwich sidereal time, with sample times in seconds from the start epoch.
+// They are flagged for degree 5 Lagrange interpolation, which follows an orbit closely for steps of up
+// to a few minutes in low Earth orbit.
+func WriteCZML(w io.Writer, sats []*Satellite, start, end time.Time, step time.Duration) error {
+	times, err := sampleTimes(start, end, step)
+	if err != nil {
+		return err
+	}
+
+	interval := start.UTC().Format(time.RFC3339Nano) + "/" + end.UTC().Format(time.RFC3339Nano)
+	packets := []czmlPacket{{
+		ID:      "document",
+		Version: "1.0",
+		Clock:   &czmlClock{Interval: interval, CurrentTime: start.UTC().Format(time.RFC3339Nano)},
+	}}
+
+	for _, sat := range sats {
+		cartesian := make([]float64, 0, 4*len(times))
+		for _, t := range times {
+			pos, _, err := PropagateECEF(*sat, t)
+			if err != nil {
+				return err
+			}
+			cartesian = append(cartesian, t.Sub(start).Seconds(), pos.X*1000, pos.Y*1000, pos.Z*1000)
+		}
+
+		packets = append(packets, czmlPacket{
+			ID:           strconv.FormatInt(sat.satnum, 10),
+			Availability: interval,
+			Position: &czmlPosition{
+				Epoch:                  start.UTC().Format(time.RFC3339Nano),
+				ReferenceFrame:         "FIXED",
+				InterpolationAlgorithm: "LAGRANGE",
+				InterpolationDegree:    czmlInterpolationDegree,
+				Cartesian:              cartesian,
+			},
+		})
+	}
+
+	return json.NewEncoder(w).Encode(packets)
+}
@@ -0,0 +1,43 @@
+package satellite
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CZML", func() {
+	iss := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+	epoch := time.Date(2008, 9, 20, 12, 25, 40, 0, time.UTC)
+
+	It("should write a document packet and a sampled position packet per satellite", func() {
+		var buf bytes.Buffer
+		Expect(WriteCZML(&buf, []*Satellite{&iss}, epoch, epoch.Add(10*time.Minute), time.Minute)).To(Succeed())
+
+		var packets []map[string]interface{}
+		Expect(json.Unmarshal(buf.Bytes(), &packets)).To(Succeed())
+		Expect(packets).To(HaveLen(2))
+		Expect(packets[0]["id"]).To(Equal("document"))
+		Expect(packets[0]["version"]).To(Equal("1.0"))
+		Expect(packets[1]["id"]).To(Equal("25544"))
+
+		position := packets[1]["position"].(map[string]interface{})
+		Expect(position["referenceFrame"]).To(Equal("FIXED"))
+		Expect(position["epoch"]).To(Equal("2008-09-20T12:25:40Z"))
+
+		cartesian := position["cartesian"].([]interface{})
+		Expect(cartesian).To(HaveLen(4 * 11))
+		Expect(cartesian[4]).To(BeNumerically("==", 60))
+
+		pos, _, err := PropagateECEF(iss, epoch)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cartesian[1]).To(BeNumerically("~", pos.X*1000, 1e-6))
+	})
+
+	It("should reject an invalid step", func() {
+		Expect(WriteCZML(&bytes.Buffer{}, []*Satellite{&iss}, epoch, epoch.Add(time.Minute), 0)).To(Equal(ErrInvalidStep))
+	})
+})
@@ -0,0 +1,82 @@
+package satellite
+
+import (
+	"time"
+)
+
+// TimeToAltitude returns the first time at or after after at which the satellite's instantaneous geodetic
+// altitude is below targetAltKm, searching up to maxHorizon ahead. The altitude is sampled 36 times per
+// nodal period and the crossing refined by bisection to within a millisecond. It returns
+// ErrNotReachedInHorizon if the altitude stays above the target, and the propagation error if SGP4
+// gives up on the orbit first.
+func TimeToAltitude(sat Satellite, targetAltKm float64, after time.Time, maxHorizon time.Duration) (time.Time, error) {
+	return firstBelow(sat, after, maxHorizon, func(t time.Time) (float64, error) {
+		_, alt, err := subpoint(sat, t)
+		return alt, err
+	}, targetAltKm)
+}
+
+// TimeToPerigeeAltitude is TimeToAltitude for the perigee altitude a(1-e) - R of the osculating orbit,
+// which drops steadily as drag lowers the orbit rather than twice an orbit. Its short-period
+// oscillation of several km sets how precisely the crossing time is defined.
+func TimeToPerigeeAltitude(sat Satellite, targetAltKm float64, after time.Time, maxHorizon time.Duration) (time.Time, error) {
+	return firstBelow(sat, after, maxHorizon, func(t time.Time) (float64, error) {
+		el, err := sat.OsculatingElementsAt(t)
+		return el.SemiMajorAxis*(1-el.Eccentricity) - sat.whichconst.radiusearthkm, err
+	}, targetAltKm)
+}
+
+// Returns the first time within maxHorizon of after at which altitude is below target
+func firstBelow(sat Satellite, after time.Time, maxHorizon time.Duration, altitude func(time.Time) (float64, error), target float64) (time.Time, error) {
+	below := func(t time.Time) (bool, error) {
+		alt, err := altitude(t)
+		return alt < target, err
+	}
+
+	step := sat.NodalPeriod() / 36
+	end := after.Add(maxHorizon)
+	prev := after
+	for t := after; ; t = t.Add(step) {
+		if t.After(end) {
+			t = end
+		}
+		b, err := below(t)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if b {
+			if t.Equal(after) {
+				return after, nil
+			}
+			return refineTransition(prev, t, false, below)
+		}
+		if t.Equal(end) {
+			return time.Time{}, ErrNotReachedInHorizon
+		}
+		prev = t
+	}
+}
+
+// EnergyTrend returns the least squares slope, in km²/s² per day, of the satellite's specific orbital
+// energy v²/2 − μ/r sampled every step from start to end inclusive. Drag removes energy, so a clearly
+// negative slope signals decay. The energy also oscillates twice per orbit under J2, so the span should
+// cover many orbits for the slope to reflect the secular trend.
+func EnergyTrend(sat Satellite, start, end time.Time, step time.Duration) (slopeKmS2PerDay float64, err error) {
+	positions, velocities, times, err := propagateRange(sat, start, end, step)
+	if err != nil {
+		return 0, err
+	}
+	if len(times) < 2 {
+		return 0, ErrInvalidTimeRange
+	}
+
+	var sumX, sumY, sumXX, sumXY float64
+	for i, t := range times {
+		v, r := velocities[i].norm(), positions[i].norm()
+		x := t.Sub(start).Hours() / 24
+		y := v*v/2 - sat.whichconst.mu/r
+		sumX, sumY, sumXX, sumXY = sumX+x, sumY+y, sumXX+x*x, sumXY+x*y
+	}
+	n := float64(len(times))
+	return (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX), nil
+}
@@ -0,0 +1,78 @@
+package satellite
+
+import (
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("decay", func() {
+	epoch := time.Date(2008, 9, 20, 12, 25, 40, 0, time.UTC)
+	// The ISS element set with bstar raised a thousandfold so that it reenters within two weeks
+	draggy := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0  10000-1 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+
+	Describe("TimeToAltitude", func() {
+		It("should find when the instantaneous altitude drops below the target", func() {
+			t, err := TimeToAltitude(draggy, 200, epoch, 30*24*time.Hour)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(t).To(BeTemporally(">", epoch.Add(6*24*time.Hour)))
+			Expect(t).To(BeTemporally("<", epoch.Add(12*24*time.Hour)))
+
+			_, alt, err := subpoint(draggy, t)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(alt).To(BeNumerically("~", 200, 0.01))
+		})
+
+		It("should report a target that isn't reached within the horizon", func() {
+			iss := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+			_, err := TimeToAltitude(iss, 200, epoch, 24*time.Hour)
+			Expect(err).To(Equal(ErrNotReachedInHorizon))
+		})
+
+		It("should search horizons longer than MaxPropagationSpan", func() {
+			iss := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+			_, err := TimeToAltitude(iss, 200, epoch, 60*24*time.Hour)
+			Expect(err).To(Equal(ErrNotReachedInHorizon))
+			_, err = TimeToPerigeeAltitude(iss, 200, epoch, 60*24*time.Hour)
+			Expect(err).To(Equal(ErrNotReachedInHorizon))
+
+			slow := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0  10000-2 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+			t, err := TimeToAltitude(slow, 200, epoch, 300*24*time.Hour)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(t).To(BeTemporally(">", epoch.Add(MaxPropagationSpan)))
+		})
+	})
+
+	Describe("TimeToPerigeeAltitude", func() {
+		It("should reach the target perigee altitude within a day of the instantaneous altitude", func() {
+			perigee, err := TimeToPerigeeAltitude(draggy, 200, epoch, 30*24*time.Hour)
+			Expect(err).NotTo(HaveOccurred())
+			instantaneous, err := TimeToAltitude(draggy, 200, epoch, 30*24*time.Hour)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(perigee).To(BeTemporally("~", instantaneous, 24*time.Hour))
+
+			el, err := draggy.OsculatingElementsAt(perigee)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(el.SemiMajorAxis*(1-el.Eccentricity) - draggy.whichconst.radiusearthkm).To(BeNumerically("~", 200, 0.01))
+		})
+	})
+	Describe("EnergyTrend", func() {
+		It("should fall steeply for a satellite under heavy drag", func() {
+			heavy, err := EnergyTrend(draggy, epoch, epoch.Add(5*24*time.Hour), 10*time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(heavy).To(BeNumerically("<", 0))
+
+			iss := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+			nominal, err := EnergyTrend(iss, epoch, epoch.Add(5*24*time.Hour), 10*time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(math.Abs(nominal)).To(BeNumerically("<", math.Abs(heavy)/100))
+		})
+
+		It("should need at least two samples", func() {
+			_, err := EnergyTrend(draggy, epoch, epoch, time.Minute)
+			Expect(err).To(Equal(ErrInvalidTimeRange))
+		})
+	})
+})
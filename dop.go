@@ -0,0 +1,91 @@
+package satellite
+
+import (
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var ErrTooFewVisible = errors.New("fewer than four satellites visible")
+var ErrDegenerateGeometry = errors.New("satellite geometry is degenerate")
+
+// GeometricDOP returns the position dilution of precision at obs at time t from the satellites in sats
+// at or above minElevationDeg: sqrt of the trace of the position part of (HᵀH)⁻¹, where each row of H
+// holds the unit line of sight to a visible satellite and a 1 for the receiver clock. Lower is better;
+// well spread geometry gives values around 2. It returns an error wrapping ErrTooFewVisible if fewer
+// than four satellites are visible and ErrDegenerateGeometry if they lie in a way that leaves the
+// solution undetermined, such as all along one cone about the observer. obs is placed on the WGS84
+// ellipsoid.
+func GeometricDOP(sats []*Satellite, obs Observer, minElevationDeg float64, t time.Time) (float64, error) {
+	minEl := minElevationDeg * DEG2RAD
+
+	var normal [4][4]float64
+	visible := 0
+	for _, sat := range sats {
+		look, err := obs.lookAngles(*sat, t)
+		if err != nil {
+			return 0, err
+		}
+		if look.El < minEl {
+			continue
+		}
+		visible++
+
+		// East, north, up and clock components of the design matrix row
+		row := [4]float64{math.Cos(look.El) * math.Sin(look.Az), math.Cos(look.El) * math.Cos(look.Az), math.Sin(look.El), 1}
+		for i := range row {
+			for j := range row {
+				normal[i][j] += row[i] * row[j]
+			}
+		}
+	}
+	if visible < 4 {
+		return 0, errors.Wrapf(ErrTooFewVisible, "%d visible", visible)
+	}
+
+	cov, ok := invert4(normal)
+	if !ok {
+		return 0, ErrDegenerateGeometry
+	}
+	return math.Sqrt(cov[0][0] + cov[1][1] + cov[2][2]), nil
+}
+
+// Inverts a 4x4 matrix by Gauss-Jordan elimination with partial pivoting, reporting false if it is singular
+func invert4(m [4][4]float64) (inv [4][4]float64, ok bool) {
+	const singular = 1e-12
+	for i := range inv {
+		inv[i][i] = 1
+	}
+
+	for col := 0; col < 4; col++ {
+		pivot := col
+		for r := col + 1; r < 4; r++ {
+			if math.Abs(m[r][col]) > math.Abs(m[pivot][col]) {
+				pivot = r
+			}
+		}
+		if math.Abs(m[pivot][col]) < singular {
+			return inv, false
+		}
+		m[col], m[pivot] = m[pivot], m[col]
+		inv[col], inv[pivot] = inv[pivot], inv[col]
+
+		scale := 1 / m[col][col]
+		for j := 0; j < 4; j++ {
+			m[col][j] *= scale
+			inv[col][j] *= scale
+		}
+		for r := 0; r < 4; r++ {
+			if r == col {
+				continue
+			}
+			f := m[r][col]
+			for j := 0; j < 4; j++ {
+				m[r][j] -= f * m[col][j]
+				inv[r][j] -= f * inv[col][j]
+			}
+		}
+	}
+	return inv, true
+}
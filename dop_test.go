@@ -0,0 +1,90 @@
+package satellite
+
+import (
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/pkg/errors"
+)
+
+var _ = Describe("GeometricDOP", func() {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	obs := Observer{Coords: LatLong{Latitude: 40 * DEG2RAD, Longitude: -75 * DEG2RAD}}
+
+	// A GPS-like constellation in medium Earth orbit
+	gnss, _ := GenerateWalker(55, 24, 6, 1, 20200, 0, epoch, "wgs72")
+
+	It("should give a PDOP typical of a navigation constellation", func() {
+		for h := 0; h < 24; h += 6 {
+			pdop, err := GeometricDOP(gnss, obs, 5, epoch.Add(time.Duration(h)*time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pdop).To(BeNumerically(">", 1))
+			Expect(pdop).To(BeNumerically("<", 4))
+		}
+	})
+
+	It("should not improve when a higher mask hides satellites", func() {
+		low, err := GeometricDOP(gnss, obs, 5, epoch)
+		Expect(err).NotTo(HaveOccurred())
+		high, err := GeometricDOP(gnss, obs, 15, epoch)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(high).To(BeNumerically(">=", low))
+	})
+
+	It("should take the lines of sight from the observer's position on the ellipsoid", func() {
+		north := Observer{Coords: LatLong{Latitude: 62 * DEG2RAD, Longitude: 25 * DEG2RAD}, Altitude: 0.2}
+		jday := jdayFromTime(epoch)
+		station := ECEFToECI(LLAToECEF(north.Coords, north.Altitude), ThetaG_JD(jday))
+
+		var normal [4][4]float64
+		for _, sat := range gnss {
+			pos, _ := PropagateAt(sat, epoch)
+			look := topocentricLookAngles(pos, station, north.Coords, jday)
+			if look.El < 5*DEG2RAD {
+				continue
+			}
+			row := [4]float64{math.Cos(look.El) * math.Sin(look.Az), math.Cos(look.El) * math.Cos(look.Az), math.Sin(look.El), 1}
+			for i := range row {
+				for j := range row {
+					normal[i][j] += row[i] * row[j]
+				}
+			}
+		}
+		cov, ok := invert4(normal)
+		Expect(ok).To(BeTrue())
+
+		pdop, err := GeometricDOP(gnss, north, 5, epoch)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pdop).To(BeNumerically("~", math.Sqrt(cov[0][0]+cov[1][1]+cov[2][2]), 1e-9))
+	})
+
+	It("should require four visible satellites", func() {
+		_, err := GeometricDOP(gnss[:2], obs, 5, epoch)
+		Expect(errors.Cause(err)).To(Equal(ErrTooFewVisible))
+	})
+
+	It("should invert a well conditioned matrix", func() {
+		m := [4][4]float64{{4, 1, 0, 2}, {1, 3, 1, 0}, {0, 1, 5, 1}, {2, 0, 1, 6}}
+		inv, ok := invert4(m)
+		Expect(ok).To(BeTrue())
+		for i := 0; i < 4; i++ {
+			for j := 0; j < 4; j++ {
+				sum := 0.0
+				for k := 0; k < 4; k++ {
+					sum += m[i][k] * inv[k][j]
+				}
+				if i == j {
+					Expect(sum).To(BeNumerically("~", 1, 1e-12))
+				} else {
+					Expect(sum).To(BeNumerically("~", 0, 1e-12))
+				}
+			}
+		}
+
+		_, ok = invert4([4][4]float64{{1, 2, 3, 4}, {2, 4, 6, 8}, {0, 1, 0, 1}, {1, 0, 1, 0}})
+		Expect(ok).To(BeFalse())
+	})
+})
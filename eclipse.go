@@ -0,0 +1,139 @@
+package satellite
+
+import (
+	"math"
+	"time"
+)
+
+// Radius of the sun in km
+const sunRadiusKm float64 = 695700.0
+
+// EclipseInterval is a span of time during which a satellite is in the Earth's umbra
+type EclipseInterval struct {
+	Entry, Exit time.Time
+}
+
+// Reports whether a satellite at satPos is inside the Earth's umbra cast by the sun at sunPos, both in
+// ECI coordinates(km). The Earth is taken to be a sphere of radius earthRadius and the umbra a cone.
+func inShadow(satPos, sunPos Vector3, earthRadius float64) bool {
+	toSun := sunPos.sub(satPos)
+	toEarth := satPos.scale(-1)
+	earthAngle := math.Asin(math.Min(1, earthRadius/satPos.norm()))
+	sunAngle := math.Asin(sunRadiusKm / toSun.norm())
+	return angleBetween(toSun, toEarth) < earthAngle-sunAngle
+}
+
+// Returns whether the satellite is in the Earth's umbra at time t
+func (sat *Satellite) shadowed(t time.Time) (bool, error) {
+	pos, _, err := propagateTime(*sat, t)
+	if err != nil {
+		return false, err
+	}
+	return inShadow(pos, sunPosition(jdayFromTime(t)), sat.whichconst.radiusearthkm), nil
+}
+
+// IsSunlit reports whether the satellite is outside the Earth's umbra at time t. The umbra is the cone
+// behind a spherical Earth from which no part of the sun's disc is visible, so a satellite in the
+// penumbra near the terminator, seeing part of the sun, counts as sunlit.
+func IsSunlit(sat *Satellite, t time.Time) (bool, error) {
+	shadowed, err := sat.shadowed(t)
+	return !shadowed, err
+}
+
+// EclipseTimes returns the intervals between start and end during which the satellite is in the Earth's
+// umbra, with entries and exits refined to within a millisecond. An eclipse under way at start begins at
+// start, and one still under way at end finishes at end. Penumbra counts as sunlit.
+//
+// The first nodal period is sampled every step. After that the search assumes at most one eclipse per
+// orbit whose entry and exit drift slowly, and only samples a window around one period after each
+// previous entry and exit. If any window doesn't hold exactly the expected transition, as when an eclipse
+// season begins or ends or for unusual geometries, the rest of the span is sampled uniformly instead.
+// Either way step must be shorter than the briefest eclipse of interest.
+func EclipseTimes(sat Satellite, start, end time.Time, step time.Duration) ([]EclipseInterval, error) {
+	initial, transitions, err := eclipseTransitions(sat, start, end, step)
+	if err != nil {
+		return nil, err
+	}
+
+	var intervals []EclipseInterval
+	for _, in := range transitionIntervals(start, end, initial, transitions) {
+		intervals = append(intervals, EclipseInterval{Entry: in.start, Exit: in.end})
+	}
+	return intervals, nil
+}
+
+// Finds the umbra entries (rising) and exits between start and end, as described by EclipseTimes
+func eclipseTransitions(sat Satellite, start, end time.Time, step time.Duration) (bool, []transition, error) {
+	period := sat.NodalPeriod()
+	if step <= 0 || end.Sub(start) <= 2*period {
+		return findTransitions(start, end, step, sat.shadowed)
+	}
+
+	initial, transitions, err := findTransitions(start, start.Add(period), step, sat.shadowed)
+	if err != nil {
+		return false, nil, err
+	}
+	if len(transitions) != 2 {
+		return findTransitions(start, end, step, sat.shadowed)
+	}
+
+	window := period / 20
+	if window < 2*step {
+		window = 2 * step
+	}
+
+	for i := 0; ; i++ {
+		last := transitions[len(transitions)-1].t
+		expected := transitions[i]
+		predicted := expected.t.Add(period)
+		if predicted.Add(-window).After(end) {
+			return initial, transitions, nil
+		}
+
+		lo, hi := predicted.Add(-window), predicted.Add(window)
+		if lo.Before(last) {
+			lo = last
+		}
+		clipped := hi.After(end)
+		if clipped {
+			hi = end
+		}
+
+		_, found, err := findTransitions(lo, hi, step, sat.shadowed)
+		if err != nil {
+			return false, nil, err
+		}
+		if len(found) == 0 && clipped {
+			return initial, transitions, nil
+		}
+		if len(found) != 1 || found[0].rising != expected.rising {
+			_, rest, err := findTransitions(last, end, step, sat.shadowed)
+			if err != nil {
+				return false, nil, err
+			}
+			return initial, append(transitions, rest...), nil
+		}
+		transitions = append(transitions, found[0])
+	}
+}
+
+// PassShadowEvents returns when the satellite enters and leaves the Earth's umbra during pass, as seen
+// by obs, refined to within a millisecond: the moments a visual observer sees it fade out and reappear.
+// ingress is nil unless the satellite enters shadow during the pass, and egress nil unless it leaves
+// shadow, so both are nil for a pass spent entirely sunlit or entirely eclipsed. Illumination doesn't
+// depend on the observer; obs only identifies whose pass it is. The pass is sampled every step.
+func PassShadowEvents(sat Satellite, obs Observer, pass Pass, step time.Duration) (ingress, egress *time.Time, err error) {
+	_, transitions, err := findTransitions(pass.AOS, pass.LOS, step, sat.shadowed)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range transitions {
+		tr := &transitions[i]
+		if tr.rising && ingress == nil {
+			ingress = &tr.t
+		} else if !tr.rising && egress == nil {
+			egress = &tr.t
+		}
+	}
+	return ingress, egress, nil
+}
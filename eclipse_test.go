@@ -0,0 +1,104 @@
+package satellite
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("eclipse", func() {
+	iss := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+	epoch := time.Date(2008, 9, 20, 12, 25, 40, 0, time.UTC)
+
+	Describe("inShadow", func() {
+		It("should shadow the night side but not the day side", func() {
+			sun := Vector3{X: astronomicalUnitKm}
+			Expect(inShadow(Vector3{X: -7000}, sun, 6378.135)).To(BeTrue())
+			Expect(inShadow(Vector3{X: 7000}, sun, 6378.135)).To(BeFalse())
+			Expect(inShadow(Vector3{Y: 7000}, sun, 6378.135)).To(BeFalse())
+		})
+	})
+
+	Describe("EclipseTimes", func() {
+		It("should find one eclipse of about half an hour per ISS orbit", func() {
+			eclipses, err := EclipseTimes(iss, epoch, epoch.Add(24*time.Hour), time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(eclipses)).To(BeNumerically("~", 16, 1))
+			for _, e := range eclipses[1 : len(eclipses)-1] {
+				Expect(e.Exit.Sub(e.Entry)).To(BeNumerically("~", 35*time.Minute, 5*time.Minute))
+			}
+		})
+
+		It("should agree with uniform sampling", func() {
+			end := epoch.Add(48 * time.Hour)
+			initial, transitions, err := eclipseTransitions(iss, epoch, end, time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			uniformInitial, uniform, err := findTransitions(epoch, end, time.Minute, iss.shadowed)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(initial).To(Equal(uniformInitial))
+			Expect(transitions).To(HaveLen(len(uniform)))
+			for i := range uniform {
+				Expect(transitions[i].rising).To(Equal(uniform[i].rising))
+				Expect(transitions[i].t).To(BeTemporally("~", uniform[i].t, 2*searchTolerance))
+			}
+		})
+	})
+	Describe("PassShadowEvents", func() {
+		obs := Observer{Coords: LatLong{Latitude: 45 * DEG2RAD, Longitude: 10 * DEG2RAD}}
+
+		It("should report the fade out and reappearance within a pass", func() {
+			eclipses, err := EclipseTimes(iss, epoch, epoch.Add(3*time.Hour), 30*time.Second)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(eclipses)).To(BeNumerically(">=", 2))
+			e := eclipses[1]
+
+			ingress, egress, err := PassShadowEvents(iss, obs, Pass{AOS: e.Entry.Add(-5 * time.Minute), LOS: e.Entry.Add(5 * time.Minute)}, 10*time.Second)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ingress).NotTo(BeNil())
+			Expect(ingress.Sub(e.Entry)).To(BeNumerically("~", 0, 2*time.Millisecond))
+			Expect(egress).To(BeNil())
+
+			ingress, egress, err = PassShadowEvents(iss, obs, Pass{AOS: e.Exit.Add(-5 * time.Minute), LOS: e.Exit.Add(5 * time.Minute)}, 10*time.Second)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ingress).To(BeNil())
+			Expect(egress).NotTo(BeNil())
+			Expect(egress.Sub(e.Exit)).To(BeNumerically("~", 0, 2*time.Millisecond))
+		})
+
+		It("should report nothing for a pass spent sunlit or eclipsed", func() {
+			eclipses, err := EclipseTimes(iss, epoch, epoch.Add(3*time.Hour), 30*time.Second)
+			Expect(err).NotTo(HaveOccurred())
+			e := eclipses[1]
+
+			for _, pass := range []Pass{
+				{AOS: e.Entry.Add(time.Minute), LOS: e.Exit.Add(-time.Minute)},
+				{AOS: e.Exit.Add(time.Minute), LOS: e.Exit.Add(10 * time.Minute)},
+			} {
+				ingress, egress, err := PassShadowEvents(iss, obs, pass, 10*time.Second)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ingress).To(BeNil())
+				Expect(egress).To(BeNil())
+			}
+		})
+	})
+	Describe("IsSunlit", func() {
+		It("should be false only during the eclipses", func() {
+			eclipses, err := EclipseTimes(iss, epoch, epoch.Add(3*time.Hour), 30*time.Second)
+			Expect(err).NotTo(HaveOccurred())
+			e := eclipses[1]
+
+			for t, want := range map[time.Time]bool{
+				e.Entry.Add(-time.Second): true,
+				e.Entry.Add(time.Second):  false,
+				e.Exit.Add(-time.Second):  false,
+				e.Exit.Add(time.Second):   true,
+			} {
+				sunlit, err := IsSunlit(&iss, t)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sunlit).To(Equal(want))
+			}
+		})
+	})
+})
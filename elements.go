@@ -0,0 +1,154 @@
+package satellite
+
+import (
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var ErrInvalidElements = errors.New("invalid orbital elements")
+
+// Elements holds the mean elements of a satellite in the units of a TLE, for building a Satellite
+// without one
+type Elements struct {
+	SatNum       int64
+	Epoch        time.Time
+	Inclination  float64 // Degrees
+	RAAN         float64 // Right ascension of the ascending node in degrees
+	Eccentricity float64
+	ArgPerigee   float64 // Degrees
+	MeanAnomaly  float64 // Degrees
+	MeanMotion   float64 // Revolutions per day
+	Bstar        float64 // Drag term in inverse Earth radii
+
+	// Derivatives of the mean motion as given in a TLE, that is halved in revolutions per day² and divided
+	// by six in revolutions per day³. SGP4 doesn't use them; they are only kept for ToTLE.
+	MeanMotionDot  float64
+	MeanMotionDDot float64
+}
+
+// ElementsToSat initializes a Satellite from mean elements in the improved operation mode, as TLEToSatV2
+// does from a TLE. Line1 and Line2 are left empty. It returns an error wrapping ErrInvalidElements if the
+// eccentricity isn't in [0, 1), the mean motion isn't positive, the epoch is outside 1957-2056, which a
+// TLE can't represent, or SGP4 rejects the elements, and ErrUnknownGravity if gravConst isn't a known model.
+func ElementsToSat(el Elements, gravConst Gravity) (Satellite, error) {
+	return elementsToSat(el, gravConst, OpsModeImproved)
+}
+
+// Initializes a Satellite from mean elements in the given operation mode, as described by ElementsToSat
+func elementsToSat(el Elements, gravConst Gravity, opsMode OpsMode) (Satellite, error) {
+	var sat Satellite
+	var err error
+	sat.gravity = gravConst
+	if sat.whichconst, err = getGravConstV2(gravConst); err != nil {
+		return Satellite{}, err
+	}
+
+	if el.Eccentricity < 0 || el.Eccentricity >= 1 || el.MeanMotion <= 0 {
+		return Satellite{}, errors.Wrapf(ErrInvalidElements, "eccentricity %g and mean motion %g", el.Eccentricity, el.MeanMotion)
+	}
+	epoch := el.Epoch.UTC()
+	if epoch.Year() < 1957 || epoch.Year() > 2056 {
+		return Satellite{}, errors.Wrapf(ErrInvalidElements, "epoch year %d", epoch.Year())
+	}
+	midnight := time.Date(epoch.Year(), epoch.Month(), epoch.Day(), 0, 0, 0, 0, time.UTC)
+	sat.epochyr = int64(epoch.Year() % 100)
+	sat.epochdays = float64(epoch.YearDay()) + epoch.Sub(midnight).Hours()/24
+
+	sat.satnum = el.SatNum
+	sat.inclo = el.Inclination
+	sat.nodeo = el.RAAN
+	sat.ecco = el.Eccentricity
+	sat.argpo = el.ArgPerigee
+	sat.mo = el.MeanAnomaly
+	sat.no = el.MeanMotion
+	sat.bstar = el.Bstar
+	sat.ndot = el.MeanMotionDot
+	sat.nddot = el.MeanMotionDDot
+
+	initSatellite(&sat, opsMode)
+	if sat.Error != 0 {
+		return Satellite{}, errors.Wrap(ErrInvalidElements, sat.ErrorStr)
+	}
+	return sat, nil
+}
+
+var ErrInvalidWalker = errors.New("walker constellation needs planes dividing the total number of satellites and phasing below the number of planes")
+
+// GenerateWalker returns the satellites of the Walker delta constellation i:t/p/f, with t totalSats
+// spread evenly over p planes whose nodes are spaced 360/p degrees apart and f the phasing: a satellite
+// is 360·f/t degrees further along its orbit than its counterpart in the previous plane. The orbits
+// have the given inclination, eccentricity and mean altitude above the gravity model's Earth radius,
+// with the perigee at the ascending node and no drag, and are numbered from 1 plane by plane.
+func GenerateWalker(inclinationDeg float64, totalSats, planes, phasing int, altitudeKm, eccentricity float64, epoch time.Time, grav Gravity) ([]*Satellite, error) {
+	if totalSats <= 0 || planes <= 0 || totalSats%planes != 0 || phasing < 0 || phasing >= planes {
+		return nil, errors.Wrapf(ErrInvalidWalker, "%d/%d/%d", totalSats, planes, phasing)
+	}
+	if altitudeKm <= 0 {
+		return nil, ErrInvalidAltitude
+	}
+	consts, err := getGravConstV2(grav)
+	if err != nil {
+		return nil, err
+	}
+
+	a := consts.radiusearthkm + altitudeKm
+	meanMotion := math.Sqrt(consts.mu/(a*a*a)) * 86400 / TWOPI
+	perPlane := totalSats / planes
+
+	sats := make([]*Satellite, 0, totalSats)
+	for p := 0; p < planes; p++ {
+		for s := 0; s < perPlane; s++ {
+			anomaly := 360*float64(s)/float64(perPlane) + 360*float64(phasing*p)/float64(totalSats)
+			sat, err := ElementsToSat(Elements{
+				SatNum:       int64(len(sats) + 1),
+				Epoch:        epoch,
+				Inclination:  inclinationDeg,
+				RAAN:         360 * float64(p) / float64(planes),
+				Eccentricity: eccentricity,
+				MeanAnomaly:  math.Mod(anomaly, 360),
+				MeanMotion:   meanMotion,
+			}, grav)
+			if err != nil {
+				return nil, err
+			}
+			sats = append(sats, &sat)
+		}
+	}
+	return sats, nil
+}
+
+// StateVectorsToSat initializes a Satellite whose epoch is t from an ECI position (km) and velocity
+// (km/s) at t, such as a GPS fix or another propagator's output, by taking the osculating elements of
+// the state as the mean elements SGP4 expects. That is only an approximation: the periodic terms SGP4
+// adds back displace the orbit by 10 to 20 km at epoch, and in low Earth orbit the error in the mean
+// motion makes it drift along track by hundreds of km a day. There is no drag. Near circular and near
+// equatorial orbits get the conventions of StateToElements, a zero argument of perigee or node. It
+// returns an error wrapping ErrInvalidElements for states that aren't bound elliptical orbits and
+// otherwise those of ElementsToSat.
+func StateVectorsToSat(pos, vel Vector3, t time.Time, gravConst Gravity) (*Satellite, error) {
+	consts, err := getGravConstV2(gravConst)
+	if err != nil {
+		return nil, err
+	}
+	el := StateToElements(pos, vel, consts.mu)
+	if !(el.SemiMajorAxis > 0) || !(el.Eccentricity < 1) {
+		return nil, errors.Wrap(ErrInvalidElements, "state is not a bound elliptical orbit")
+	}
+
+	a := el.SemiMajorAxis
+	sat, err := ElementsToSat(Elements{
+		Epoch:        t,
+		Inclination:  el.Inclination * RAD2DEG,
+		RAAN:         el.RAAN * RAD2DEG,
+		Eccentricity: el.Eccentricity,
+		ArgPerigee:   el.ArgPerigee * RAD2DEG,
+		MeanAnomaly:  el.MeanAnomaly * RAD2DEG,
+		MeanMotion:   math.Sqrt(consts.mu/(a*a*a)) * 86400 / TWOPI,
+	}, gravConst)
+	if err != nil {
+		return nil, err
+	}
+	return &sat, nil
+}
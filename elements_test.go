@@ -0,0 +1,128 @@
+package satellite
+
+import (
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/pkg/errors"
+)
+
+var _ = Describe("elements", func() {
+	Describe("ElementsToSat", func() {
+		It("should propagate like the TLE the elements came from", func() {
+			iss := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+			epoch := time.Date(2008, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(263.51782528 * 24 * float64(time.Hour)))
+			sat, err := ElementsToSat(Elements{
+				SatNum:       25544,
+				Epoch:        epoch,
+				Inclination:  51.6416,
+				RAAN:         247.4627,
+				Eccentricity: 0.0006703,
+				ArgPerigee:   130.5360,
+				MeanAnomaly:  325.0288,
+				MeanMotion:   15.72125391,
+				Bstar:        -0.11606e-4,
+			}, "wgs72")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sat.satnum).To(Equal(int64(25544)))
+
+			for _, tsince := range []float64{0, 90, 1440} {
+				want, _, err := PropagateMinutes(&iss, tsince)
+				Expect(err).NotTo(HaveOccurred())
+				got, _, err := PropagateMinutes(&sat, tsince)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(got.sub(want).norm()).To(BeNumerically("<", 1e-3))
+			}
+		})
+
+		It("should reject elements a TLE or SGP4 can't represent", func() {
+			epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+			for _, el := range []Elements{
+				{Epoch: epoch, MeanMotion: 15, Eccentricity: 1},
+				{Epoch: epoch, MeanMotion: 0},
+				{Epoch: time.Date(2060, 1, 1, 0, 0, 0, 0, time.UTC), MeanMotion: 15},
+			} {
+				_, err := ElementsToSat(el, "wgs72")
+				Expect(errors.Cause(err)).To(Equal(ErrInvalidElements))
+			}
+
+			_, err := ElementsToSat(Elements{Epoch: epoch, MeanMotion: 15}, "wgs99")
+			Expect(errors.Cause(err)).To(Equal(ErrUnknownGravity))
+		})
+	})
+
+	Describe("GenerateWalker", func() {
+		epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		It("should spread the satellites over evenly spaced planes with the phase offset", func() {
+			sats, err := GenerateWalker(53, 24, 3, 1, 550, 0, epoch, "wgs72")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sats).To(HaveLen(24))
+
+			for i, sat := range sats {
+				plane, slot := i/8, i%8
+				Expect(sat.satnum).To(Equal(int64(i + 1)))
+				Expect(sat.inclo).To(BeNumerically("~", 53*DEG2RAD, 1e-12))
+				Expect(sat.nodeo).To(BeNumerically("~", float64(plane)*TWOPI/3, 1e-12))
+				Expect(sat.mo).To(BeNumerically("~", float64(slot)*TWOPI/8+float64(plane)*TWOPI/24, 1e-12))
+			}
+
+			pos, _, err := PropagateMinutes(sats[0], 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pos.norm() - 6378.135).To(BeNumerically("~", 550, 15))
+		})
+
+		It("should reject inconsistent Walker parameters", func() {
+			for _, tpf := range [][3]int{{24, 5, 1}, {24, 3, 3}, {0, 1, 0}, {24, 0, 0}, {24, 3, -1}} {
+				_, err := GenerateWalker(53, tpf[0], tpf[1], tpf[2], 550, 0, epoch, "wgs72")
+				Expect(errors.Cause(err)).To(Equal(ErrInvalidWalker))
+			}
+			_, err := GenerateWalker(53, 24, 3, 1, -10, 0, epoch, "wgs72")
+			Expect(err).To(Equal(ErrInvalidAltitude))
+		})
+	})
+
+	Describe("StateVectorsToSat", func() {
+		iss := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+
+		It("should approximately reproduce the state it was seeded from", func() {
+			t := iss.Epoch().Add(time.Hour)
+			pos, vel := PropagateAt(&iss, t)
+			sat, err := StateVectorsToSat(pos, vel, t, "wgs72")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sat.Epoch()).To(BeTemporally("~", t, time.Millisecond))
+			Expect(sat.Inclination()).To(BeNumerically("~", 51.64, 0.1))
+
+			got, _ := PropagateAt(sat, t)
+			Expect(got.sub(pos).norm()).To(BeNumerically("<", 20))
+			got, _ = PropagateAt(sat, t.Add(90*time.Minute))
+			want, _ := PropagateAt(&iss, t.Add(90*time.Minute))
+			Expect(got.sub(want).norm()).To(BeNumerically("<", 100))
+		})
+
+		It("should fall back to the conventional elements for circular equatorial orbits", func() {
+			mu := getGravConst(GravityWGS72).mu
+			sat, err := StateVectorsToSat(Vector3{X: 7000}, Vector3{Y: math.Sqrt(mu / 7000)}, iss.Epoch(), "wgs72")
+			Expect(err).NotTo(HaveOccurred())
+			for _, angle := range []float64{sat.Inclination(), sat.RAAN(), sat.ArgPerigee(), sat.MeanAnomaly()} {
+				Expect(math.IsNaN(angle)).To(BeFalse())
+			}
+			Expect(sat.Inclination()).To(BeZero())
+			Expect(sat.RAAN()).To(BeZero())
+			Expect(sat.ArgPerigee()).To(BeZero())
+
+			pos, _ := PropagateAt(sat, iss.Epoch())
+			Expect(pos.sub(Vector3{X: 7000}).norm()).To(BeNumerically("<", 20))
+		})
+
+		It("should reject states that aren't bound orbits", func() {
+			_, err := StateVectorsToSat(Vector3{X: 7000}, Vector3{Y: 20}, iss.Epoch(), "wgs72")
+			Expect(errors.Cause(err)).To(Equal(ErrInvalidElements))
+			_, err = StateVectorsToSat(Vector3{X: 7000}, Vector3{Y: 7.5}, iss.Epoch(), "wgs99")
+			Expect(errors.Cause(err)).To(Equal(ErrUnknownGravity))
+		})
+	})
+})
@@ -0,0 +1,72 @@
+package satellite
+
+import (
+	"math/rand"
+	"time"
+)
+
+// ElementSigmas holds one standard deviation of uncertainty for each mean element, in the units of
+// Elements. Zero leaves an element unperturbed.
+type ElementSigmas struct {
+	Inclination  float64 // Degrees
+	RAAN         float64 // Degrees
+	Eccentricity float64
+	ArgPerigee   float64 // Degrees
+	MeanAnomaly  float64 // Degrees
+	MeanMotion   float64 // Revolutions per day
+	Bstar        float64 // Inverse Earth radii
+}
+
+// Seed of the random perturbations of PerturbedEnsemble, fixed so that results are reproducible
+const ensembleSeed = 1
+
+// PerturbedEnsemble returns the positions in ECI coordinates(km) at time t of n copies of the satellite
+// whose mean elements are each offset by a normally distributed amount with the standard deviations in
+// sigmas. This is a crude Monte Carlo of TLE uncertainty: real element errors are correlated, mostly
+// along track, and aren't published with TLEs, so the spread only gives a feel for how quickly an
+// uncertainty of a given size grows. The perturbations come from a fixed seed, so the same arguments
+// always give the same positions. An eccentricity perturbed below zero is clamped to zero.
+func PerturbedEnsemble(sat Satellite, t time.Time, sigmas ElementSigmas, n int) ([]Vector3, error) {
+	if n <= 0 {
+		return nil, ErrInvalidCount
+	}
+	if sat.init == "" {
+		return nil, ErrNotInitialized
+	}
+	nominal := Elements{
+		SatNum:       sat.SatNum(),
+		Epoch:        sat.Epoch(),
+		Inclination:  sat.Inclination(),
+		RAAN:         sat.RAAN(),
+		Eccentricity: sat.Eccentricity(),
+		ArgPerigee:   sat.ArgPerigee(),
+		MeanAnomaly:  sat.MeanAnomaly(),
+		MeanMotion:   sat.MeanMotion(),
+		Bstar:        sat.Bstar(),
+	}
+
+	rng := rand.New(rand.NewSource(ensembleSeed))
+	positions := make([]Vector3, n)
+	for i := range positions {
+		el := nominal
+		el.Inclination += sigmas.Inclination * rng.NormFloat64()
+		el.RAAN += sigmas.RAAN * rng.NormFloat64()
+		el.Eccentricity += sigmas.Eccentricity * rng.NormFloat64()
+		el.ArgPerigee += sigmas.ArgPerigee * rng.NormFloat64()
+		el.MeanAnomaly += sigmas.MeanAnomaly * rng.NormFloat64()
+		el.MeanMotion += sigmas.MeanMotion * rng.NormFloat64()
+		el.Bstar += sigmas.Bstar * rng.NormFloat64()
+		if el.Eccentricity < 0 {
+			el.Eccentricity = 0
+		}
+
+		perturbed, err := elementsToSat(el, sat.gravity, OpsMode(sat.operationmode))
+		if err != nil {
+			return nil, err
+		}
+		if positions[i], _, err = propagateTime(perturbed, t); err != nil {
+			return nil, err
+		}
+	}
+	return positions, nil
+}
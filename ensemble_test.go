@@ -0,0 +1,58 @@
+package satellite
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PerturbedEnsemble", func() {
+	iss := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+	epoch := time.Date(2008, 9, 20, 12, 25, 40, 0, time.UTC)
+	sigmas := ElementSigmas{MeanMotion: 1e-5, Bstar: 1e-5}
+
+	It("should reproduce the nominal position without perturbations", func() {
+		nominal, _, err := propagateTime(iss, epoch.Add(6*time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		positions, err := PerturbedEnsemble(iss, epoch.Add(6*time.Hour), ElementSigmas{}, 3)
+		Expect(err).NotTo(HaveOccurred())
+		for _, pos := range positions {
+			Expect(pos.sub(nominal).norm()).To(BeNumerically("<", 1e-3))
+		}
+	})
+
+	It("should be deterministic", func() {
+		a, err := PerturbedEnsemble(iss, epoch.Add(time.Hour), sigmas, 20)
+		Expect(err).NotTo(HaveOccurred())
+		b, err := PerturbedEnsemble(iss, epoch.Add(time.Hour), sigmas, 20)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(a).To(Equal(b))
+	})
+
+	It("should spread further from the nominal position over time", func() {
+		spread := func(t time.Time) float64 {
+			nominal, _, err := propagateTime(iss, t)
+			Expect(err).NotTo(HaveOccurred())
+			positions, err := PerturbedEnsemble(iss, t, sigmas, 50)
+			Expect(err).NotTo(HaveOccurred())
+			worst := 0.0
+			for _, pos := range positions {
+				if d := pos.sub(nominal).norm(); d > worst {
+					worst = d
+				}
+			}
+			return worst
+		}
+		soon, later := spread(epoch.Add(time.Hour)), spread(epoch.Add(3*24*time.Hour))
+		Expect(soon).To(BeNumerically(">", 0))
+		Expect(later).To(BeNumerically(">", 5*soon))
+	})
+
+	It("should reject a non-positive count or an uninitialized satellite", func() {
+		_, err := PerturbedEnsemble(iss, epoch, sigmas, 0)
+		Expect(err).To(Equal(ErrInvalidCount))
+		_, err = PerturbedEnsemble(Satellite{}, epoch, sigmas, 1)
+		Expect(err).To(Equal(ErrNotInitialized))
+	})
+})
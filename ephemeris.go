@@ -0,0 +1,67 @@
+package satellite
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Frame names a reference frame for exported positions and velocities
+type Frame string
+
+const (
+	FrameTEME Frame = "TEME" // True Equator, Mean Equinox, the inertial frame SGP4 works in
+	FrameECEF Frame = "ECEF" // Earth fixed, rotated from TEME by Greenwich sidereal time
+)
+
+var ErrUnknownFrame = errors.New("unknown reference frame")
+
+// Returns the satellite's position (km) and velocity (km/s) at time t in the given frame
+func stateInFrame(sat Satellite, t time.Time, frame Frame) (Vector3, Vector3, error) {
+	switch frame {
+	case FrameTEME:
+		return propagateTime(sat, t)
+	case FrameECEF:
+		return PropagateECEF(sat, t)
+	}
+	return Vector3{}, Vector3{}, errors.Wrapf(ErrUnknownFrame, "%q", frame)
+}
+
+// WriteEphemerisTabular writes the satellite's state every step from start to end inclusive to w as
+// whitespace aligned text, one row per sample. Lines starting with # are header comments naming the
+// satellite, frame and columns:
+//
+//	UTC  JD_UTC  X_km  Y_km  Z_km  VX_km/s  VY_km/s  VZ_km/s
+//
+// Times are ISO 8601 UTC with millisecond precision and the matching julian date. Positions are in km
+// to 1 m and velocities in km/s to 1 mm/s, in the requested frame. Nothing is written if frame is unknown.
+func WriteEphemerisTabular(w io.Writer, sat Satellite, start, end time.Time, step time.Duration, frame Frame) error {
+	if frame != FrameTEME && frame != FrameECEF {
+		return errors.Wrapf(ErrUnknownFrame, "%q", frame)
+	}
+	times, err := sampleTimes(start, end, step)
+	if err != nil {
+		return err
+	}
+
+	header := fmt.Sprintf("# satellite %d\n# frame %s\n# %-24s %17s %14s %14s %14s %12s %12s %12s\n",
+		sat.satnum, frame, "UTC", "JD_UTC", "X_km", "Y_km", "Z_km", "VX_km/s", "VY_km/s", "VZ_km/s")
+	if _, err := io.WriteString(w, header); err != nil {
+		return err
+	}
+
+	for _, t := range times {
+		pos, vel, err := stateInFrame(sat, t, frame)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "  %-24s %17.9f %14.3f %14.3f %14.3f %12.6f %12.6f %12.6f\n",
+			t.UTC().Format("2006-01-02T15:04:05.000Z"), jdayFromTime(t), pos.X, pos.Y, pos.Z, vel.X, vel.Y, vel.Z)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package satellite
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/pkg/errors"
+)
+
+var _ = Describe("ephemeris", func() {
+	iss := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+	epoch := time.Date(2008, 9, 20, 12, 25, 40, 0, time.UTC)
+
+	Describe("WriteEphemerisTabular", func() {
+		It("should write a header and one row of eight columns per sample", func() {
+			var buf bytes.Buffer
+			Expect(WriteEphemerisTabular(&buf, iss, epoch, epoch.Add(5*time.Minute), time.Minute, FrameTEME)).To(Succeed())
+
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			Expect(lines).To(HaveLen(3 + 6))
+			Expect(lines[1]).To(Equal("# frame TEME"))
+
+			fields := strings.Fields(lines[3])
+			Expect(fields).To(HaveLen(8))
+			Expect(fields[0]).To(Equal("2008-09-20T12:25:40.000Z"))
+
+			pos, _, err := propagateTime(iss, epoch)
+			Expect(err).NotTo(HaveOccurred())
+			x, err := strconv.ParseFloat(fields[2], 64)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(x).To(BeNumerically("~", pos.X, 0.001))
+		})
+
+		It("should write Earth fixed coordinates on request", func() {
+			var buf bytes.Buffer
+			Expect(WriteEphemerisTabular(&buf, iss, epoch, epoch, time.Minute, FrameECEF)).To(Succeed())
+			fields := strings.Fields(strings.Split(buf.String(), "\n")[3])
+
+			pos, _, err := PropagateECEF(iss, epoch)
+			Expect(err).NotTo(HaveOccurred())
+			x, err := strconv.ParseFloat(fields[2], 64)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(x).To(BeNumerically("~", pos.X, 0.001))
+		})
+
+		It("should reject an unknown frame", func() {
+			var buf bytes.Buffer
+			err := WriteEphemerisTabular(&buf, iss, epoch, epoch, time.Minute, "J2000")
+			Expect(errors.Cause(err)).To(Equal(ErrUnknownFrame))
+			Expect(buf.Len()).To(BeZero())
+		})
+	})
+})
@@ -0,0 +1,42 @@
+package satellite
+
+import (
+	"github.com/pkg/errors"
+)
+
+var ErrInvalidStep = errors.New("time step must be positive")
+var ErrInvalidTimeRange = errors.New("end time precedes start time")
+var ErrNotInitialized = errors.New("satellite has not been initialized by sgp4init")
+var ErrInvalidCount = errors.New("count must be positive")
+var ErrNotReachedInHorizon = errors.New("target not reached within the search horizon")
+
+// Errors SGP4 reports through Satellite.Error, by the codes of Vallado's implementation
+var (
+	ErrEccentricityOutOfRange          = errors.New("mean eccentricity not within range 0.0 <= e < 1.0")
+	ErrNegativeMeanMotion              = errors.New("mean motion is less than zero")
+	ErrPerturbedEccentricityOutOfRange = errors.New("perturbed eccentricity not within range 0.0 <= e <= 1.0")
+	ErrNegativeSemiLatusRectum         = errors.New("semilatus rectum is less than zero")
+	ErrSubOrbital                      = errors.New("epoch elements are sub-orbital")
+	ErrDecayed                         = errors.New("satellite has decayed")
+)
+
+var sgp4Errors = map[int64]error{
+	1: ErrEccentricityOutOfRange,
+	2: ErrNegativeMeanMotion,
+	3: ErrPerturbedEccentricityOutOfRange,
+	4: ErrNegativeSemiLatusRectum,
+	5: ErrSubOrbital,
+	6: ErrDecayed,
+}
+
+// Err returns the sentinel error for the code sgp4init or the last propagation left in sat.Error, or nil
+// if it is zero. Unknown codes give an error quoting ErrorStr.
+func (sat *Satellite) Err() error {
+	if sat.Error == 0 {
+		return nil
+	}
+	if err, ok := sgp4Errors[sat.Error]; ok {
+		return err
+	}
+	return errors.Errorf("sgp4 error %d: %s", sat.Error, sat.ErrorStr)
+}
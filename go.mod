@@ -5,6 +5,6 @@ go 1.13
 require (
 	github.com/onsi/ginkgo v1.2.1-0.20160509182050-5437a97bf824
 	github.com/onsi/gomega v0.0.0-20160516222431-c73e51675ad2
-	github.com/pkg/errors v0.9.1 // indirect
+	github.com/pkg/errors v0.9.1
 	gopkg.in/yaml.v2 v2.0.0-20160301204022-a83829b6f129
 )
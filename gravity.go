@@ -1,8 +1,9 @@
 package satellite
 
 import (
-	"log"
 	"math"
+
+	"github.com/pkg/errors"
 )
 
 // Holds variables that are dependent upon selected gravity model
@@ -18,8 +19,50 @@ const (
 	GravityWGS84    Gravity = "wgs84"
 )
 
-// Returns a GravConst with correct information on requested model provided through the name parameter
-func getGravConst(name Gravity) (grav GravConst) {
+var ErrUnknownGravity = errors.New("unknown gravity model")
+
+// GravConstParams holds the constants of a gravity model as SGP4 uses them
+type GravConstParams struct {
+	RadiusEarthKm float64 // Equatorial radius in km
+	Mu            float64 // Gravitational parameter in km³/s²
+	J2, J3, J4    float64 // Zonal harmonics
+	XKE           float64 // sqrt(mu) in Earth radii^1.5 per minute
+}
+
+// GravityConstants returns the constants of the gravity model g, the same ones a Satellite initialized with
+// it propagates with, or an error wrapping ErrUnknownGravity. For WGS72old XKE is the rounded value of the
+// original code rather than the one derived from Mu. The result is a copy, so changing it has no effect on
+// propagation.
+func GravityConstants(g Gravity) (GravConstParams, error) {
+	grav, err := getGravConstV2(g)
+	if err != nil {
+		return GravConstParams{}, err
+	}
+	return GravConstParams{
+		RadiusEarthKm: grav.radiusearthkm,
+		Mu:            grav.mu,
+		J2:            grav.j2,
+		J3:            grav.j3,
+		J4:            grav.j4,
+		XKE:           grav.xke,
+	}, nil
+}
+
+// Returns a GravConst with correct information on requested model provided through the name parameter.
+// Unknown models, including the empty one, are reported through Log and fall back to WGS72; use
+// getGravConstV2 to reject them.
+func getGravConst(name Gravity) GravConst {
+	grav, err := getGravConstV2(name)
+	if err != nil {
+		logf("%v, using %s", err, GravityWGS72)
+		grav, _ = getGravConstV2(GravityWGS72)
+	}
+	return grav
+}
+
+// Returns a GravConst with correct information on requested model provided through the name parameter,
+// or an error wrapping ErrUnknownGravity
+func getGravConstV2(name Gravity) (grav GravConst, err error) {
 	switch name {
 	case GravityWGS72Old:
 		grav.mu = 398600.79964
@@ -49,7 +92,7 @@ func getGravConst(name Gravity) (grav GravConst) {
 		grav.j4 = -0.00000161098761
 		grav.j3oj2 = grav.j3 / grav.j2
 	default:
-		log.Fatal(name, "is not a valid gravity model")
+		err = errors.Wrapf(ErrUnknownGravity, "%q", name)
 	}
 
 	return
@@ -0,0 +1,268 @@
+package satellite
+
+import (
+	"math"
+	"time"
+)
+
+// Mean radius of the Earth in km used for distances along the surface
+const meanEarthRadiusKm float64 = 6371.0088
+
+// Returns the geodetic subpoint in radians, with longitude in (-pi, pi], and the altitude in km
+// of the satellite at time t
+func subpoint(sat Satellite, t time.Time) (LatLong, float64, error) {
+	pos, _, err := propagateTime(sat, t)
+	if err != nil {
+		return LatLong{}, 0, err
+	}
+	alt, _, ll := ECIToLLA(pos, gstime(jdayFromTime(t)))
+	return ll, alt, nil
+}
+
+// Returns the great-circle distance in km between two points given in radians, using the haversine formula
+func greatCircleDistance(a, b LatLong) float64 {
+	sinDLat := math.Sin((b.Latitude - a.Latitude) / 2)
+	sinDLon := math.Sin((b.Longitude - a.Longitude) / 2)
+	h := sinDLat*sinDLat + math.Cos(a.Latitude)*math.Cos(b.Latitude)*sinDLon*sinDLon
+	return 2 * meanEarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// ClosestGroundApproach returns the time between start and end at which the satellite's subpoint
+// passes nearest to target (latitude and longitude in radians), along with the great-circle distance
+// in km between the two at that time. The track is sampled every step and the closest sample is then
+// refined by parabolic interpolation of the distance.
+func ClosestGroundApproach(sat Satellite, target LatLong, start, end time.Time, step time.Duration) (time.Time, float64, error) {
+	distance := func(t time.Time) (float64, error) {
+		ll, _, err := subpoint(sat, t)
+		if err != nil {
+			return 0, err
+		}
+		return greatCircleDistance(ll, target), nil
+	}
+
+	times, err := sampleTimes(start, end, step)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+
+	best, bestDist := 0, math.Inf(1)
+	for i, t := range times {
+		d, err := distance(t)
+		if err != nil {
+			return time.Time{}, 0, err
+		}
+		if d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+
+	if best == 0 || best == len(times)-1 {
+		return times[best], bestDist, nil
+	}
+	return refineMinimum(times[best-1], times[best], times[best+1], bestDist, distance)
+}
+
+// SubpointMotion returns the compass heading in degrees [0, 360) of the satellite's velocity relative to
+// the rotating Earth at time t, and its flight-path angle in degrees above (positive) or below the local
+// horizontal at the geodetic subpoint.
+func SubpointMotion(sat Satellite, t time.Time) (headingDeg, flightPathAngleDeg float64, err error) {
+	pos, vel, err := propagateTime(sat, t)
+	if err != nil {
+		return 0, 0, err
+	}
+	gmst := gstime(jdayFromTime(t))
+	_, _, ll := ECIToLLA(pos, gmst)
+	_, ecefVel := eciToECEFState(pos, vel, gmst)
+
+	east, north, up := ecefToENU(ecefVel, ll)
+	headingDeg = math.Mod(math.Atan2(east, north)*RAD2DEG+360, 360)
+	flightPathAngleDeg = math.Asin(up/ecefVel.norm()) * RAD2DEG
+	return headingDeg, flightPathAngleDeg, nil
+}
+
+// CrossingDirection selects which latitude crossings LatitudeCrossingsDirection reports
+type CrossingDirection int
+
+const (
+	CrossingAny        CrossingDirection = iota // Both northbound and southbound crossings
+	CrossingAscending                           // Northbound crossings only
+	CrossingDescending                          // Southbound crossings only
+)
+
+// LatitudeCrossings returns every time between start and end at which the satellite's geodetic
+// subpoint crosses latitudeDeg in either direction, along with the subpoint in radians at each
+// crossing. The track is sampled every step and each crossing is refined by bisection to within
+// a millisecond; step must be short enough that the subpoint can't cross and return between samples.
+func LatitudeCrossings(sat Satellite, latitudeDeg float64, start, end time.Time, step time.Duration) ([]time.Time, []LatLong, error) {
+	return LatitudeCrossingsDirection(sat, latitudeDeg, CrossingAny, start, end, step)
+}
+
+// LatitudeCrossingsDirection is LatitudeCrossings restricted to crossings in the given direction
+func LatitudeCrossingsDirection(sat Satellite, latitudeDeg float64, dir CrossingDirection, start, end time.Time, step time.Duration) ([]time.Time, []LatLong, error) {
+	target := latitudeDeg * DEG2RAD
+	_, transitions, err := findTransitions(start, end, step, func(t time.Time) (bool, error) {
+		ll, _, err := subpoint(sat, t)
+		return ll.Latitude > target, err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var times []time.Time
+	var points []LatLong
+	for _, tr := range transitions {
+		if (dir == CrossingAscending && !tr.rising) || (dir == CrossingDescending && tr.rising) {
+			continue
+		}
+		ll, _, err := subpoint(sat, tr.t)
+		if err != nil {
+			return nil, nil, err
+		}
+		times = append(times, tr.t)
+		points = append(points, ll)
+	}
+	return times, points, nil
+}
+
+// GEOSlotError returns a geostationary satellite's offset from its slot at targetLongitudeDeg at time t.
+// eastWestDeg is the subpoint longitude minus the target, wrapped into (-180, 180], and is positive when
+// the satellite sits east of its slot. northSouthDeg is the subpoint latitude, the excursion out of the
+// equatorial plane, which swings daily with an amplitude close to the orbit's inclination.
+func (sat *Satellite) GEOSlotError(targetLongitudeDeg float64, t time.Time) (eastWestDeg, northSouthDeg float64, err error) {
+	ll, _, err := subpoint(*sat, t)
+	if err != nil {
+		return 0, 0, err
+	}
+	eastWestDeg = wrapPi(ll.Longitude-targetLongitudeDeg*DEG2RAD) * RAD2DEG
+	northSouthDeg = ll.Latitude * RAD2DEG
+	return eastWestDeg, northSouthDeg, nil
+}
+
+// Half width of the central difference used for ground track rates
+const groundRateStep = time.Second
+
+// SubpointApproachRate returns the rate in km/s at which the great-circle distance between the
+// satellite's subpoint and obs (latitude and longitude in radians) changes at time t. It is negative
+// while the subpoint approaches obs. Unlike the slant range rate it ignores altitude, which makes it
+// the quantity a map based tracker shows. It is computed by central difference over ±1 s.
+func SubpointApproachRate(sat Satellite, obs LatLong, t time.Time) (kmPerSec float64, err error) {
+	before, _, err := subpoint(sat, t.Add(-groundRateStep))
+	if err != nil {
+		return 0, err
+	}
+	after, _, err := subpoint(sat, t.Add(groundRateStep))
+	if err != nil {
+		return 0, err
+	}
+	delta := greatCircleDistance(after, obs) - greatCircleDistance(before, obs)
+	return delta / (2 * groundRateStep.Seconds()), nil
+}
+
+// OrbitSubpoints returns count subpoints in radians equally spaced in mean anomaly around one orbit,
+// starting with the subpoint at atTime. Mean anomaly advances uniformly in time, so they are spaced by
+// the anomalistic period over count; the Earth's rotation during the orbit is included.
+func OrbitSubpoints(sat Satellite, atTime time.Time, count int) ([]LatLong, error) {
+	if count <= 0 {
+		return nil, ErrInvalidCount
+	}
+	spacing := sat.AnomalisticPeriod() / time.Duration(count)
+
+	points := make([]LatLong, count)
+	for i := range points {
+		ll, _, err := subpoint(sat, atTime.Add(time.Duration(i)*spacing))
+		if err != nil {
+			return nil, err
+		}
+		points[i] = ll
+	}
+	return points, nil
+}
+
+// Angular distance in degrees from a pole within which SubpointRate reports no longitude rate
+const polarCapDeg = 0.01
+
+// SubpointRate returns how fast the satellite's geodetic subpoint moves at time t, in degrees of latitude
+// and longitude per second, for dead reckoning a map marker between propagated points. The rates come
+// from the Earth fixed velocity projected onto the local tangent plane, divided by the meridian and
+// parallel radii of curvature of the WGS84 ellipsoid at the satellite's altitude. The longitude rate
+// grows without bound towards a pole, where longitude is undefined, so it is reported as zero within
+// 0.01 degrees of one.
+func SubpointRate(sat Satellite, t time.Time) (dLatDegPerSec, dLonDegPerSec float64, err error) {
+	pos, vel, err := propagateTime(sat, t)
+	if err != nil {
+		return 0, 0, err
+	}
+	gmst := gstime(jdayFromTime(t))
+	alt, _, ll := ECIToLLA(pos, gmst)
+	_, ecefVel := eciToECEFState(pos, vel, gmst)
+	east, north, _ := ecefToENU(ecefVel, ll)
+
+	// WGS84 radii of curvature, matching the ellipsoid of ECIToLLA
+	const a, b = 6378.137, 6356.7523142
+	e2 := 1 - (b*b)/(a*a)
+	sinLat := math.Sin(ll.Latitude)
+	w := math.Sqrt(1 - e2*sinLat*sinLat)
+	meridian := a * (1 - e2) / (w * w * w)
+	primeVertical := a / w
+
+	dLatDegPerSec = north / (meridian + alt) * RAD2DEG
+	if 90-math.Abs(ll.Latitude*RAD2DEG) > polarCapDeg {
+		dLonDegPerSec = east / ((primeVertical + alt) * math.Cos(ll.Latitude)) * RAD2DEG
+	}
+	return dLatDegPerSec, dLonDegPerSec, nil
+}
+
+// GroundTrack returns the geodetic subpoint in degrees, with longitude in (-180, 180], of the satellite
+// at count times spaced step apart from start, along with those times. Each point uses the sidereal
+// time of its own timestamp. GroundTrackWithAltitude also returns the altitudes.
+func GroundTrack(sat *Satellite, start time.Time, step time.Duration, count int) ([]LatLong, []time.Time, error) {
+	points, _, times, err := GroundTrackWithAltitude(sat, start, step, count)
+	return points, times, err
+}
+
+// GroundTrackWithAltitude is GroundTrack with the geodetic altitude in km of each point in a parallel slice
+func GroundTrackWithAltitude(sat *Satellite, start time.Time, step time.Duration, count int) ([]LatLong, []float64, []time.Time, error) {
+	if count <= 0 {
+		return nil, nil, nil, ErrInvalidCount
+	}
+	if step <= 0 {
+		return nil, nil, nil, ErrInvalidStep
+	}
+
+	points := make([]LatLong, count)
+	altitudes := make([]float64, count)
+	times := make([]time.Time, count)
+	for i := range points {
+		times[i] = start.Add(time.Duration(i) * step)
+		ll, alt, err := subpoint(*sat, times[i])
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		points[i] = LatLong{Latitude: ll.Latitude * RAD2DEG, Longitude: ll.Longitude * RAD2DEG}
+		altitudes[i] = alt
+	}
+	return points, altitudes, times, nil
+}
+
+// SwathWidthKm returns the cross-track width on the ground, in km, seen at nadir by a sensor with a half
+// angle field of view of fovHalfAngleDeg from altitudeKm. It measures the arc along a spherical Earth of
+// the WGS72 radius, which is wider than the flat Earth 2·h·tan(fov) by the curvature. The result is NaN
+// if the altitude isn't above the surface or the field of view reaches past the horizon.
+func SwathWidthKm(altitudeKm, fovHalfAngleDeg float64) float64 {
+	re := getGravConst(GravityWGS72).radiusearthkm
+	eta := fovHalfAngleDeg * DEG2RAD
+	if altitudeKm <= 0 || eta < 0 {
+		return math.NaN()
+	}
+
+	// Angular radius of the Earth as seen from the satellite
+	rho := math.Asin(re / (re + altitudeKm))
+	if eta >= rho {
+		return math.NaN()
+	}
+
+	// Elevation of the satellite seen from the swath edge, then the Earth central angle to it
+	elevation := math.Acos(math.Sin(eta) / math.Sin(rho))
+	lambda := math.Pi/2 - eta - elevation
+	return 2 * re * lambda
+}
@@ -0,0 +1,209 @@
+package satellite
+
+import (
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ground track", func() {
+	iss := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+	epoch := time.Date(2008, 9, 20, 12, 25, 40, 0, time.UTC)
+
+	Describe("ClosestGroundApproach", func() {
+		It("should find the time the subpoint passes over a target", func() {
+			overflight := epoch.Add(30*time.Minute + 17*time.Second)
+			target, _, err := subpoint(iss, overflight)
+			Expect(err).NotTo(HaveOccurred())
+
+			t, dist, err := ClosestGroundApproach(iss, target, epoch, epoch.Add(time.Hour), time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(t).To(BeTemporally("~", overflight, time.Second))
+			Expect(dist).To(BeNumerically("<", 5))
+		})
+	})
+	Describe("SubpointMotion", func() {
+		It("should report a near zero flight-path angle for a near circular orbit", func() {
+			for m := 0; m < 92; m += 4 {
+				_, fpa, err := SubpointMotion(iss, epoch.Add(time.Duration(m)*time.Minute))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fpa).To(BeNumerically("~", 0, 0.25))
+			}
+		})
+
+		It("should head south-east on a descending pass and north-east on an ascending one", func() {
+			heading, _, err := SubpointMotion(iss, epoch.Add(20*time.Minute))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(heading).To(BeNumerically("~", 135, 15))
+
+			heading, _, err = SubpointMotion(iss, epoch.Add(70*time.Minute))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(heading).To(BeNumerically("~", 40, 15))
+		})
+	})
+	Describe("LatitudeCrossings", func() {
+		It("should find alternating crossings of a latitude twice per orbit", func() {
+			times, points, err := LatitudeCrossings(iss, 45, epoch, epoch.Add(3*time.Hour), time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(times).To(HaveLen(len(points)))
+			Expect(times).To(HaveLen(4))
+			for _, p := range points {
+				Expect(p.Latitude * RAD2DEG).To(BeNumerically("~", 45, 1e-3))
+			}
+		})
+
+		It("should split crossings by direction", func() {
+			all, _, err := LatitudeCrossings(iss, 45, epoch, epoch.Add(3*time.Hour), time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			up, _, err := LatitudeCrossingsDirection(iss, 45, CrossingAscending, epoch, epoch.Add(3*time.Hour), time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			down, _, err := LatitudeCrossingsDirection(iss, 45, CrossingDescending, epoch, epoch.Add(3*time.Hour), time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(up) + len(down)).To(Equal(len(all)))
+
+			// The ISS starts just past its northernmost point, so it first crosses 45°N heading south
+			Expect(down[0]).To(Equal(all[0]))
+			Expect(up[0].After(down[0])).To(BeTrue())
+		})
+	})
+	Describe("GEOSlotError", func() {
+		geo := TLEToSat("1 24208U 96044A   06177.04061740 -.00000094  00000-0  10000-3 0  1600", "2 24208   3.8536  80.0121 0026640 311.0977  48.3000  1.00778054 36119", "wgs72")
+		geoEpoch := time.Date(2006, 6, 26, 0, 58, 29, 0, time.UTC)
+
+		It("should measure east-west offset from the slot longitude", func() {
+			ll, _, err := subpoint(geo, geoEpoch)
+			Expect(err).NotTo(HaveOccurred())
+			slot := ll.Longitude * RAD2DEG
+
+			ew, _, err := geo.GEOSlotError(slot, geoEpoch)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ew).To(BeNumerically("~", 0, 1e-9))
+
+			ew, _, err = geo.GEOSlotError(slot-1, geoEpoch)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ew).To(BeNumerically("~", 1, 1e-9))
+
+			ew, _, err = geo.GEOSlotError(slot+360, geoEpoch)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ew).To(BeNumerically("~", 0, 1e-9))
+		})
+
+		It("should swing north and south by about the inclination over a day", func() {
+			maxNS := 0.0
+			for h := 0; h < 24; h++ {
+				_, ns, err := geo.GEOSlotError(0, geoEpoch.Add(time.Duration(h)*time.Hour))
+				Expect(err).NotTo(HaveOccurred())
+				maxNS = math.Max(maxNS, math.Abs(ns))
+			}
+			Expect(maxNS).To(BeNumerically("~", 3.8536, 0.2))
+		})
+	})
+	Describe("SubpointApproachRate", func() {
+		It("should change sign at the closest approach", func() {
+			overflight := epoch.Add(30*time.Minute + 17*time.Second)
+			target, _, err := subpoint(iss, overflight)
+			Expect(err).NotTo(HaveOccurred())
+
+			approaching, err := SubpointApproachRate(iss, target, overflight.Add(-2*time.Minute))
+			Expect(err).NotTo(HaveOccurred())
+			receding, err := SubpointApproachRate(iss, target, overflight.Add(2*time.Minute))
+			Expect(err).NotTo(HaveOccurred())
+
+			// The subpoint moves at about 7 km/s relative to the ground
+			Expect(approaching).To(BeNumerically("~", -7, 0.5))
+			Expect(receding).To(BeNumerically("~", 7, 0.5))
+		})
+	})
+	Describe("OrbitSubpoints", func() {
+		It("should start at the current subpoint and space points a fraction of an orbit apart", func() {
+			points, err := OrbitSubpoints(iss, epoch, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(points).To(HaveLen(10))
+
+			start, _, err := subpoint(iss, epoch)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(points[0]).To(Equal(start))
+
+			// A tenth of an orbit is 36 degrees of arc, about 4000 km along the ground
+			for i := 1; i < len(points); i++ {
+				Expect(greatCircleDistance(points[i-1], points[i])).To(BeNumerically("~", 4000, 400))
+			}
+		})
+
+		It("should reject a non-positive count", func() {
+			_, err := OrbitSubpoints(iss, epoch, 0)
+			Expect(err).To(Equal(ErrInvalidCount))
+		})
+	})
+	Describe("SwathWidthKm", func() {
+		It("should approach the flat Earth width for a narrow field of view", func() {
+			flat := 2 * 700 * math.Tan(1*DEG2RAD)
+			Expect(SwathWidthKm(700, 1)).To(BeNumerically("~", flat, 0.01))
+		})
+
+		It("should be wider than the flat Earth width for a wide field of view", func() {
+			flat := 2 * 700 * math.Tan(45*DEG2RAD)
+			Expect(SwathWidthKm(700, 45)).To(BeNumerically(">", flat*1.05))
+		})
+
+		It("should be NaN when the field of view misses the Earth", func() {
+			// From 700 km the Earth's limb is about 64 degrees off nadir
+			Expect(math.IsNaN(SwathWidthKm(700, 70))).To(BeTrue())
+			Expect(math.IsNaN(SwathWidthKm(0, 10))).To(BeTrue())
+		})
+	})
+	Describe("GroundTrack", func() {
+		It("should return the subpoints in degrees at each step", func() {
+			points, times, err := GroundTrack(&iss, epoch, time.Minute, 200)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(points).To(HaveLen(200))
+			Expect(times).To(HaveLen(200))
+			Expect(times[199]).To(Equal(epoch.Add(199 * time.Minute)))
+
+			for i, p := range points {
+				Expect(p.Longitude).To(BeNumerically(">", -180))
+				Expect(p.Longitude).To(BeNumerically("<=", 180))
+				Expect(math.Abs(p.Latitude)).To(BeNumerically("<", 52))
+
+				ll, _, err := subpoint(iss, times[i])
+				Expect(err).NotTo(HaveOccurred())
+				Expect(p.Latitude).To(BeNumerically("~", ll.Latitude*RAD2DEG, 1e-12))
+				Expect(p.Longitude).To(BeNumerically("~", ll.Longitude*RAD2DEG, 1e-12))
+			}
+		})
+
+		It("should return the altitudes in a parallel slice", func() {
+			points, altitudes, _, err := GroundTrackWithAltitude(&iss, epoch, time.Minute, 90)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(altitudes).To(HaveLen(len(points)))
+			for _, alt := range altitudes {
+				Expect(alt).To(BeNumerically("~", 350, 30))
+			}
+		})
+
+		It("should reject a non-positive count or step", func() {
+			_, _, err := GroundTrack(&iss, epoch, time.Minute, 0)
+			Expect(err).To(Equal(ErrInvalidCount))
+			_, _, err = GroundTrack(&iss, epoch, 0, 10)
+			Expect(err).To(Equal(ErrInvalidStep))
+		})
+	})
+	Describe("SubpointRate", func() {
+		It("should match the change in the subpoint over a second", func() {
+			for _, offset := range []time.Duration{0, 10 * time.Minute, 40 * time.Minute, 70 * time.Minute} {
+				t := epoch.Add(offset)
+				dLat, dLon, err := SubpointRate(iss, t)
+				Expect(err).NotTo(HaveOccurred())
+
+				before, _, err := subpoint(iss, t.Add(-500*time.Millisecond))
+				Expect(err).NotTo(HaveOccurred())
+				after, _, err := subpoint(iss, t.Add(500*time.Millisecond))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dLat).To(BeNumerically("~", (after.Latitude-before.Latitude)*RAD2DEG, 1e-5))
+				Expect(dLon).To(BeNumerically("~", wrapPi(after.Longitude-before.Longitude)*RAD2DEG, 1e-5))
+			}
+		})
+	})
+})
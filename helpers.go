@@ -1,10 +1,11 @@
 package satellite
 
 import (
-	"log"
 	"math"
 	"strconv"
 	"strings"
+
+	"github.com/pkg/errors"
 )
 
 // Constants
@@ -13,6 +14,8 @@ const DEG2RAD float64 = math.Pi / 180.0
 const RAD2DEG float64 = 180.0 / math.Pi
 const XPDOTP float64 = 1440.0 / (2.0 * math.Pi)
 
+var ErrInvalidTLE = errors.New("invalid TLE")
+
 // Holds latitude and Longitude in either degrees or radians
 type LatLong struct {
 	Latitude, Longitude float64
@@ -26,46 +29,211 @@ type Vector3 struct {
 // Holds an azimuth, elevation and range
 type LookAngles struct {
 	Az, El, Rg float64
+	Rr         float64 // Range rate in km/s, positive when receding; zero from ECIToLookAngles, which has no velocity
 }
 
-// Parses a two line element dataset into a Satellite struct
+// Shortest a TLE line may be once trailing whitespace is removed: columns 1-68, the checksum being optional
+const tleMinLineLength = 68
+
+// Longest a TLE line may be once trailing whitespace is removed, including the checksum in column 69
+const tleMaxLineLength = 69
+
+// Parses a two line element dataset into a Satellite struct.
+// Malformed lines and fields are reported through Log and left as zero, and short lines no longer panic;
+// use ParseTLEV2 to get an error instead.
 func ParseTLE(line1, line2 string, gravConst Gravity) (sat Satellite) {
+	sat, err := parseTLE(line1, line2, gravConst)
+	if err != nil {
+		logf("%v", err)
+	}
+	return
+}
+
+// Parses a two line element dataset into a Satellite struct, returning an error wrapping
+// ErrInvalidTLE if a field is malformed or ErrUnknownGravity if gravConst isn't a known model.
+func ParseTLEV2(line1, line2 string, gravConst Gravity) (Satellite, error) {
+	sat, err := parseTLE(line1, line2, gravConst)
+	if err != nil {
+		return Satellite{}, err
+	}
+	return sat, nil
+}
+
+// Checks that a TLE line has the expected line number and a length the fixed columns fit in, returning
+// it with trailing whitespace such as a carriage return removed
+func normalizeTLELine(line string, number byte) (string, error) {
+	line = strings.TrimRight(line, " \t\r\n")
+	if strings.ContainsRune(line, '\t') {
+		return line, errors.Wrapf(ErrInvalidTLE, "line %c contains a tab, which shifts the fixed columns", number)
+	}
+	if len(line) < tleMinLineLength || len(line) > tleMaxLineLength {
+		return line, errors.Wrapf(ErrInvalidTLE, "line %c is %d characters long, want %d or %d", number, len(line), tleMinLineLength, tleMaxLineLength)
+	}
+	if line[0] != number {
+		return line, errors.Wrapf(ErrInvalidTLE, "line %c starts with %q", number, line[0])
+	}
+	return line, nil
+}
+
+// Parses a two line element dataset, returning the fields parsed so far alongside the first error encountered
+func parseTLE(line1, line2 string, gravConst Gravity) (sat Satellite, err error) {
+	sat.Line1 = line1
+	sat.Line2 = line2
+
+	if line1, err = normalizeTLELine(line1, '1'); err != nil {
+		return
+	}
+	if line2, err = normalizeTLELine(line2, '2'); err != nil {
+		return
+	}
 	sat.Line1 = line1
 	sat.Line2 = line2
 
 	sat.Error = 0
-	sat.whichconst = getGravConst(gravConst)
+	sat.gravity = gravConst
+	sat.whichconst, err = getGravConstV2(gravConst)
+	if err != nil {
+		return
+	}
+
+	var p tleFieldParser
 
 	// LINE 1 BEGIN
-	sat.satnum = parseInt(strings.TrimSpace(line1[2:7]))
-	sat.epochyr = parseInt(line1[18:20])
-	sat.epochdays = parseFloat(line1[20:32])
+	sat.satnum = p.parseSatnum(line1[2:7])
+	sat.classification = line1[7]
+	sat.intldesg = strings.TrimSpace(line1[9:17])
+	sat.epochyr = p.parseEpochYear(line1[18:20])
+	sat.epochdays = p.parseFloat("epoch day", line1[20:32])
 
 	// These three can be negative / positive
-	sat.ndot = parseFloat(strings.Replace(line1[33:43], " ", "", 2))
-	sat.nddot = parseFloat(strings.Replace(line1[44:45]+"."+line1[45:50]+"e"+line1[50:52], " ", "", 2))
-	sat.bstar = parseFloat(strings.Replace(line1[53:54]+"."+line1[54:59]+"e"+line1[59:61], " ", "", 2))
+	sat.ndot = p.parseFloat("first derivative of mean motion", strings.Replace(line1[33:43], " ", "", 2))
+	sat.nddot = p.parseFloat("second derivative of mean motion", strings.Replace(line1[44:45]+"."+line1[45:50]+"e"+line1[50:52], " ", "", 2))
+	sat.bstar = p.parseFloat("bstar", strings.Replace(line1[53:54]+"."+line1[54:59]+"e"+line1[59:61], " ", "", 2))
+	sat.ephtype = p.parseOptionalInt("ephemeris type", line1[62:63])
+	sat.elnum = p.parseOptionalInt("element set number", line1[64:68])
 	// LINE 1 END
 
 	// LINE 2 BEGIN
-	sat.inclo = parseFloat(strings.Replace(line2[8:16], " ", "", 2))
-	sat.nodeo = parseFloat(strings.Replace(line2[17:25], " ", "", 2))
-	sat.ecco = parseFloat("." + line2[26:33])
-	sat.argpo = parseFloat(strings.Replace(line2[34:42], " ", "", 2))
-	sat.mo = parseFloat(strings.Replace(line2[43:51], " ", "", 2))
-	sat.no = parseFloat(strings.Replace(line2[52:63], " ", "", 2))
+	sat.inclo = p.parseFloat("inclination", strings.Replace(line2[8:16], " ", "", 2))
+	sat.nodeo = p.parseFloat("right ascension of ascending node", strings.Replace(line2[17:25], " ", "", 2))
+	sat.ecco = p.parseFloat("eccentricity", "."+line2[26:33])
+	sat.argpo = p.parseFloat("argument of perigee", strings.Replace(line2[34:42], " ", "", 2))
+	sat.mo = p.parseFloat("mean anomaly", strings.Replace(line2[43:51], " ", "", 2))
+	sat.no = p.parseFloat("mean motion", strings.Replace(line2[52:63], " ", "", 2))
+	sat.revnum = p.parseOptionalInt("revolution number", line2[63:68])
 	// LINE 2 END
+
+	err = p.err
 	return
 }
 
-// Converts a two line element data set into a Satellite struct and runs sgp4init
+// Parses TLE fields, remembering the first malformed field so callers can check once at the end
+type tleFieldParser struct {
+	err error
+}
+
+// Parses a field into a float64 value
+func (p *tleFieldParser) parseFloat(name, strIn string) float64 {
+	ret, err := strconv.ParseFloat(strIn, 64)
+	if err != nil && p.err == nil {
+		p.err = errors.Wrapf(ErrInvalidTLE, "malformed %s %q", name, strIn)
+	}
+	return ret
+}
+
+// Parses a field into an int64 value
+func (p *tleFieldParser) parseInt(name, strIn string) int64 {
+	ret, err := strconv.ParseInt(strIn, 10, 0)
+	if err != nil && p.err == nil {
+		p.err = errors.Wrapf(ErrInvalidTLE, "malformed %s %q", name, strIn)
+	}
+	return ret
+}
+
+// Parses a field into an int64 value, taking a blank field as zero
+func (p *tleFieldParser) parseOptionalInt(name, strIn string) int64 {
+	strIn = strings.TrimSpace(strIn)
+	if strIn == "" {
+		return 0
+	}
+	return p.parseInt(name, strIn)
+}
+
+// Parses the two digit epoch year, which unlike other integer fields may not be blank padded or signed
+func (p *tleFieldParser) parseEpochYear(strIn string) int64 {
+	if strings.Trim(strIn, "0123456789") != "" {
+		if p.err == nil {
+			p.err = errors.Wrapf(ErrInvalidTLE, "malformed epoch year %q", strIn)
+		}
+		return 0
+	}
+	return p.parseInt("epoch year", strIn)
+}
+
+// Expands the two digit epoch year of a TLE into a four digit year, 57-99 being 1957-1999 and 00-56
+// 2000-2056. Years outside 0-99, which the parser rejects, give 0 rather than a plausible wrong epoch.
+func expandEpochYear(yy int64) int {
+	switch {
+	case yy < 0 || yy > 99:
+		return 0
+	case yy < 57:
+		return int(yy) + 2000
+	default:
+		return int(yy) + 1900
+	}
+}
+
+// Parses a satellite catalog number, which may use the Alpha-5 scheme
+func (p *tleFieldParser) parseSatnum(strIn string) int64 {
+	ret, err := DecodeAlpha5(strIn)
+	if err != nil && p.err == nil {
+		p.err = errors.Wrapf(ErrInvalidTLE, "malformed satellite number %q", strIn)
+	}
+	return ret
+}
+
+// Selects between the original AFSPC behaviour of SGP4 and the improved mode
+type OpsMode string
+
+var ErrUnknownOpsMode = errors.New("unknown operation mode")
+
+const (
+	OpsModeAFSPC    OpsMode = "a"
+	OpsModeImproved OpsMode = "i"
+)
+
+// Converts a two line element data set into a Satellite struct and runs sgp4init in the improved operation mode
 func TLEToSat(line1, line2 string, gravConst Gravity) Satellite {
-	//sat := Satellite{Line1: line1, Line2: line2}
+	return TLEToSatWithOpsMode(line1, line2, gravConst, OpsModeImproved)
+}
+
+// Converts a two line element data set into a Satellite struct and runs sgp4init in the given operation mode.
+// OpsModeAFSPC reproduces AFSPC's sidereal time at epoch and deep space node handling exactly, which is only
+// needed to match their output; OpsModeImproved is recommended otherwise. The AFSPC sidereal time at epoch
+// ignores UT1MinusUTC.
+func TLEToSatWithOpsMode(line1, line2 string, gravConst Gravity, opsMode OpsMode) Satellite {
 	sat := ParseTLE(line1, line2, gravConst)
+	initSatellite(&sat, opsMode)
+	return sat
+}
 
-	opsmode := "i"
+// Converts a two line element data set into a Satellite struct and runs sgp4init in the improved operation mode,
+// returning the errors described by ParseTLEV2 instead of logging them
+func TLEToSatV2(line1, line2 string, gravConst Gravity) (Satellite, error) {
+	sat, err := ParseTLEV2(line1, line2, gravConst)
+	if err != nil {
+		return Satellite{}, err
+	}
+	initSatellite(&sat, OpsModeImproved)
+	return sat, nil
+}
+
+// Converts the parsed elements of sat from TLE units into radians and radians per minute and runs sgp4init
+func initSatellite(sat *Satellite, opsMode OpsMode) {
+	opsmode := string(opsMode)
 
 	sat.no = sat.no / XPDOTP
+	sat.noKozai = sat.no
 	sat.ndot = sat.ndot / (XPDOTP * 1440.0)
 	sat.nddot = sat.nddot / (XPDOTP * 1440.0 * 1440)
 
@@ -74,36 +242,32 @@ func TLEToSat(line1, line2 string, gravConst Gravity) Satellite {
 	sat.argpo = sat.argpo * DEG2RAD
 	sat.mo = sat.mo * DEG2RAD
 
-	var year int64 = 0
-	if sat.epochyr < 57 {
-		year = sat.epochyr + 2000
-	} else {
-		year = sat.epochyr + 1900
-	}
-
-	mon, day, hr, min, sec := days2mdhms(year, sat.epochdays)
+	year := expandEpochYear(sat.epochyr)
+	mon, day, hr, min, sec := days2mdhms(int64(year), sat.epochdays)
 
-	sat.jdsatepoch = JDay(int(year), int(mon), int(day), int(hr), int(min), int(sec))
+	// JDay only takes whole seconds, so add the fraction separately rather than moving the epoch by up to a second
+	whole, frac := math.Modf(sec)
+	sat.jdsatepoch = JDay(year, int(mon), int(day), int(hr), int(min), int(whole)) + frac/86400.0
 
-	sgp4init(&opsmode, sat.jdsatepoch-2433281.5, &sat)
-
-	return sat
+	sgp4init(&opsmode, sat.jdsatepoch-2433281.5, sat)
 }
 
-// Parses a string into a float64 value.
+// Parses a string into a float64 value, reporting malformed input through Log and returning 0
 func parseFloat(strIn string) (ret float64) {
 	ret, err := strconv.ParseFloat(strIn, 64)
 	if err != nil {
-		log.Fatal(err)
+		logf("%v", err)
+		return 0
 	}
 	return ret
 }
 
-// Parses a string into a int64 value.
+// Parses a string into a int64 value, reporting malformed input through Log and returning 0
 func parseInt(strIn string) (ret int64) {
 	ret, err := strconv.ParseInt(strIn, 10, 0)
 	if err != nil {
-		log.Fatal(err)
+		logf("%v", err)
+		return 0
 	}
 	return ret
 }
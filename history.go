@@ -0,0 +1,92 @@
+package satellite
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var ErrEmptyHistory = errors.New("tle history contains no satellites")
+var ErrMixedHistory = errors.New("tle history contains more than one satellite number")
+
+// TLEHistory holds successive element sets of a single object ordered by epoch
+type TLEHistory struct {
+	sats []Satellite
+}
+
+// NewTLEHistory creates a TLEHistory from initialized satellites sharing one catalog number.
+// The satellites may be given in any order.
+func NewTLEHistory(sats []Satellite) (*TLEHistory, error) {
+	if len(sats) == 0 {
+		return nil, ErrEmptyHistory
+	}
+
+	sorted := make([]Satellite, len(sats))
+	copy(sorted, sats)
+	for _, sat := range sorted {
+		if sat.satnum != sorted[0].satnum {
+			return nil, ErrMixedHistory
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].jdsatepoch < sorted[j].jdsatepoch
+	})
+
+	return &TLEHistory{sats: sorted}, nil
+}
+
+// Returns the index of the first element set with an epoch after the julian date jday
+func (h *TLEHistory) after(jday float64) int {
+	return sort.Search(len(h.sats), func(i int) bool {
+		return h.sats[i].jdsatepoch > jday
+	})
+}
+
+// Nearest returns the element set whose epoch is closest to t
+func (h *TLEHistory) Nearest(t time.Time) *Satellite {
+	jday := jdayFromTime(t)
+	i := h.after(jday)
+	if i == len(h.sats) {
+		return &h.sats[i-1]
+	}
+	if i > 0 && jday-h.sats[i-1].jdsatepoch <= h.sats[i].jdsatepoch-jday {
+		return &h.sats[i-1]
+	}
+	return &h.sats[i]
+}
+
+// Propagate calculates position and velocity at t using the element set nearest to t
+func (h *TLEHistory) Propagate(t time.Time) (position, velocity Vector3, err error) {
+	return propagateTime(*h.Nearest(t), t)
+}
+
+// PropagateBlended calculates position and velocity at t by propagating the two element sets whose
+// epochs straddle t and linearly interpolating between the two states, weighted by how close t is
+// to each epoch. Outside the span of the history the nearest element set is used on its own.
+//
+// This is a pragmatic blend that removes the jump at the switchover between element sets when
+// building long archival tracks. It is not a fit to either set and the blended state doesn't
+// satisfy the equations of motion exactly.
+func (h *TLEHistory) PropagateBlended(t time.Time) (position, velocity Vector3, err error) {
+	jday := jdayFromTime(t)
+	i := h.after(jday)
+	if i == 0 || i == len(h.sats) {
+		return h.Propagate(t)
+	}
+
+	before, after := h.sats[i-1], h.sats[i]
+	posBefore, velBefore, err := propagateTime(before, t)
+	if err != nil {
+		return
+	}
+	posAfter, velAfter, err := propagateTime(after, t)
+	if err != nil {
+		return
+	}
+
+	w := (jday - before.jdsatepoch) / (after.jdsatepoch - before.jdsatepoch)
+	position = posBefore.scale(1 - w).add(posAfter.scale(w))
+	velocity = velBefore.scale(1 - w).add(velAfter.scale(w))
+	return
+}
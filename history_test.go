@@ -0,0 +1,94 @@
+package satellite
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TLEHistory", func() {
+	line2 := "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537"
+	withEpoch := func(epoch string) Satellite {
+		return TLEToSat("1 25544U 98067A   "+epoch+" -.00002182  00000-0 -11606-4 0  2927", line2, "wgs72")
+	}
+	// The same elements at three epochs, so that the element sets disagree between their epochs
+	first, second, third := withEpoch("08264.51782528"), withEpoch("08265.01782528"), withEpoch("08265.76782528")
+
+	var history *TLEHistory
+	BeforeEach(func() {
+		var err error
+		history, err = NewTLEHistory([]Satellite{third, first, second})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should pick the element set with the closest epoch", func() {
+		Expect(history.Nearest(first.Epoch().Add(-time.Hour)).Epoch()).To(Equal(first.Epoch()))
+		Expect(history.Nearest(first.Epoch().Add(5 * time.Hour)).Epoch()).To(Equal(first.Epoch()))
+		Expect(history.Nearest(first.Epoch().Add(7 * time.Hour)).Epoch()).To(Equal(second.Epoch()))
+		Expect(history.Nearest(third.Epoch().Add(-10 * time.Hour)).Epoch()).To(Equal(second.Epoch()))
+		Expect(history.Nearest(third.Epoch().Add(-time.Hour)).Epoch()).To(Equal(third.Epoch()))
+
+		t := second.Epoch().Add(2 * time.Hour)
+		pos, vel, err := history.Propagate(t)
+		Expect(err).NotTo(HaveOccurred())
+		wantPos, wantVel := PropagateAt(&second, t)
+		Expect(pos).To(Equal(wantPos))
+		Expect(vel).To(Equal(wantVel))
+	})
+
+	It("should match the single element sets at the bracketing epochs", func() {
+		for _, sat := range []Satellite{first, second, third} {
+			pos, vel, err := history.PropagateBlended(sat.Epoch())
+			Expect(err).NotTo(HaveOccurred())
+			wantPos, wantVel := PropagateAt(&sat, sat.Epoch())
+			Expect(pos.sub(wantPos).norm()).To(BeNumerically("<", 1e-6))
+			Expect(vel.sub(wantVel).norm()).To(BeNumerically("<", 1e-9))
+		}
+
+		// Approaching the later epoch the weight of the later element set tends to one
+		t := second.Epoch().Add(-time.Millisecond)
+		pos, _, err := history.PropagateBlended(t)
+		Expect(err).NotTo(HaveOccurred())
+		wantPos, _ := PropagateAt(&second, t)
+		Expect(pos.sub(wantPos).norm()).To(BeNumerically("<", 0.01))
+	})
+
+	It("should be continuous through the midpoint where the nearest element set changes", func() {
+		mid := first.Epoch().Add(second.Epoch().Sub(first.Epoch()) / 2)
+		before, _, err := history.PropagateBlended(mid.Add(-time.Millisecond))
+		Expect(err).NotTo(HaveOccurred())
+		after, _, err := history.PropagateBlended(mid.Add(time.Millisecond))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(after.sub(before).norm()).To(BeNumerically("<", 0.1))
+
+		nearestBefore, _, _ := history.Propagate(mid.Add(-time.Millisecond))
+		nearestAfter, _, _ := history.Propagate(mid.Add(time.Millisecond))
+		Expect(nearestAfter.sub(nearestBefore).norm()).To(BeNumerically(">", 10))
+	})
+
+	It("should fall back to the nearest element set outside the history", func() {
+		for _, c := range []struct {
+			t   time.Time
+			sat Satellite
+		}{
+			{first.Epoch().Add(-6 * time.Hour), first},
+			{third.Epoch().Add(6 * time.Hour), third},
+		} {
+			pos, vel, err := history.PropagateBlended(c.t)
+			Expect(err).NotTo(HaveOccurred())
+			wantPos, wantVel := PropagateAt(&c.sat, c.t)
+			Expect(pos).To(Equal(wantPos))
+			Expect(vel).To(Equal(wantVel))
+		}
+	})
+
+	It("should reject empty and mixed histories", func() {
+		_, err := NewTLEHistory(nil)
+		Expect(err).To(Equal(ErrEmptyHistory))
+
+		other := TLEToSat("1 25545U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25545  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+		_, err = NewTLEHistory([]Satellite{first, other})
+		Expect(err).To(Equal(ErrMixedHistory))
+	})
+})
@@ -0,0 +1,22 @@
+package satellite
+
+import (
+	"log"
+	"os"
+)
+
+// Logger is satisfied by *log.Logger and most structured loggers' printf adapters
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// Log receives the messages the legacy, non-error-returning helpers emit when they meet bad input.
+// Set it to route those messages elsewhere, or to nil to silence them.
+var Log Logger = log.New(os.Stderr, "satellite: ", log.LstdFlags)
+
+// Writes a message to Log if one is set
+func logf(format string, v ...interface{}) {
+	if Log != nil {
+		Log.Printf(format, v...)
+	}
+}
@@ -0,0 +1,45 @@
+package satellite
+
+import (
+	"math"
+)
+
+// CircularizeAtApogeeDeltaV returns the velocity change in km/s of a single prograde burn at apogee that
+// raises perigee to the apogee radius, leaving a circular orbit. The apsides come from the mean
+// semi-major axis and eccentricity, and the burn from vis-viva with the satellite's gravity model.
+func (sat *Satellite) CircularizeAtApogeeDeltaV() (float64, error) {
+	if sat.init == "" {
+		return 0, ErrNotInitialized
+	}
+	mu := sat.whichconst.mu
+	a := sat.SemiMajorAxisKm()
+	ra := a * (1 + sat.ecco)
+
+	circular := math.Sqrt(mu / ra)
+	atApogee := math.Sqrt(mu * (2/ra - 1/a))
+	return circular - atApogee, nil
+}
+
+// HohmannDeltaV returns the two burns in km/s and the transfer time in seconds of a Hohmann transfer
+// between circular, coplanar orbits at fromAltKm and toAltKm above the gravity model's Earth radius.
+// The burns are positive when prograde, as both are when raising the orbit, and negative when lowering
+// it. An orbit at or below the surface gives ErrInvalidAltitude.
+func HohmannDeltaV(fromAltKm, toAltKm float64, grav Gravity) (dv1, dv2, transferTime float64, err error) {
+	consts, err := getGravConstV2(grav)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	if fromAltKm <= 0 || toAltKm <= 0 {
+		return 0, 0, 0, ErrInvalidAltitude
+	}
+	mu := consts.mu
+	r1 := consts.radiusearthkm + fromAltKm
+	r2 := consts.radiusearthkm + toAltKm
+	a := (r1 + r2) / 2
+
+	// Vis-viva on the transfer ellipse at departure and arrival
+	dv1 = math.Sqrt(mu*(2/r1-1/a)) - math.Sqrt(mu/r1)
+	dv2 = math.Sqrt(mu/r2) - math.Sqrt(mu*(2/r2-1/a))
+	transferTime = math.Pi * math.Sqrt(a*a*a/mu)
+	return dv1, dv2, transferTime, nil
+}
@@ -0,0 +1,58 @@
+package satellite
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/pkg/errors"
+)
+
+var _ = Describe("maneuver", func() {
+	Describe("CircularizeAtApogeeDeltaV", func() {
+		It("should need about 1.5 km/s to circularize a geostationary transfer orbit", func() {
+			// Perigee ~260 km and apogee ~36,000 km
+			gto := TLEToSat("1 23599U 95029B   06171.76535463  .00085586  12891-6  12956-2 0  2905", "2 23599   6.9327   0.2849 7300000 274.4436  25.2425  2.25000000123555", "wgs72")
+			dv, err := gto.CircularizeAtApogeeDeltaV()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dv).To(BeNumerically("~", 1.5, 0.15))
+		})
+
+		It("should be almost nothing for a near circular orbit", func() {
+			iss := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+			dv, err := iss.CircularizeAtApogeeDeltaV()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dv).To(BeNumerically("~", 0.0026, 0.0002))
+		})
+
+		It("should reject an uninitialized satellite", func() {
+			_, err := (&Satellite{}).CircularizeAtApogeeDeltaV()
+			Expect(err).To(Equal(ErrNotInitialized))
+		})
+	})
+	Describe("HohmannDeltaV", func() {
+		It("should match the textbook transfer from low Earth orbit to geostationary", func() {
+			dv1, dv2, transfer, err := HohmannDeltaV(300, 35786, GravityWGS72)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dv1).To(BeNumerically("~", 2.43, 0.01))
+			Expect(dv2).To(BeNumerically("~", 1.46, 0.01))
+			Expect(transfer / 3600).To(BeNumerically("~", 5.27, 0.02))
+		})
+
+		It("should give retrograde burns of the same size for the reverse transfer", func() {
+			up1, up2, upTime, err := HohmannDeltaV(400, 800, GravityWGS72)
+			Expect(err).NotTo(HaveOccurred())
+			down1, down2, downTime, err := HohmannDeltaV(800, 400, GravityWGS72)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(down1).To(BeNumerically("~", -up2, 1e-12))
+			Expect(down2).To(BeNumerically("~", -up1, 1e-12))
+			Expect(downTime).To(BeNumerically("~", upTime, 1e-9))
+		})
+
+		It("should reject altitudes below the surface and unknown gravity models", func() {
+			_, _, _, err := HohmannDeltaV(-1, 800, GravityWGS72)
+			Expect(err).To(Equal(ErrInvalidAltitude))
+			_, _, _, err = HohmannDeltaV(400, 800, "wgs99")
+			Expect(errors.Cause(err)).To(Equal(ErrUnknownGravity))
+		})
+	})
+})
@@ -0,0 +1,233 @@
+package satellite
+
+import (
+	"math"
+	"time"
+)
+
+// Observer holds the location of a ground station
+type Observer struct {
+	Coords   LatLong // Geodetic latitude and longitude in radians
+	Altitude float64 // Altitude above the WGS84 ellipsoid in km
+}
+
+// Returns the observer's position in Earth Centered Inertial coordinates(km) on the WGS84 ellipsoid at
+// the given julian date
+func (obs Observer) eci(jday float64) Vector3 {
+	return geodeticECI(obs.Coords, obs.Altitude, jday)
+}
+
+// Returns the position in Earth Centered Inertial coordinates(km) at the given julian date of an observer
+// at geodetic coordinates coords and alt km above the WGS84 ellipsoid, rather than the sphere of LLAToECI
+func geodeticECI(coords LatLong, alt, jday float64) Vector3 {
+	return ECEFToECI(LLAToECEF(coords, alt), ThetaG_JD(jday))
+}
+
+// RADecSample holds the topocentric right ascension and declination of a satellite at a point in time
+type RADecSample struct {
+	T       time.Time
+	RA, Dec float64 // radians
+}
+
+// PassRADecTrack samples the satellite's topocentric right ascension and declination as seen by
+// obs every step from the pass's AOS to its LOS inclusive, with obs on the WGS84 ellipsoid as for
+// ObserverRaDec. The coordinates are referred to the same inertial frame as the propagator output, and
+// are suitable for driving an equatorial mount.
+func PassRADecTrack(sat Satellite, obs Observer, pass Pass, step time.Duration) ([]RADecSample, error) {
+	positions, _, times, err := propagateRange(sat, pass.AOS, pass.LOS, step)
+	if err != nil {
+		return nil, err
+	}
+
+	track := make([]RADecSample, len(times))
+	for i, t := range times {
+		ra, dec := ECIToRaDec(positions[i], geodeticECI(obs.Coords, obs.Altitude, jdayFromTime(t)))
+		track[i] = RADecSample{T: t, RA: ra, Dec: dec}
+	}
+
+	return track, nil
+}
+
+// Returns the look angles in radians, range in km and range rate in km/s from obs to the satellite at
+// time t
+func (obs Observer) lookAngles(sat Satellite, t time.Time) (LookAngles, error) {
+	pos, vel, err := propagateTime(sat, t)
+	if err != nil {
+		return LookAngles{}, err
+	}
+	return ECIToLookAnglesV2(pos, vel, obs.Coords, obs.Altitude, jdayFromTime(t)), nil
+}
+
+// ObserverLookAngles returns the azimuth in [0, 2pi) and elevation in radians, range in km and range rate
+// in km/s of the satellite at time t as seen from an observer at observer (geodetic latitude and
+// longitude in radians) and altKm above the WGS84 ellipsoid. It propagates the satellite, computes the
+// sidereal time of t and places the observer in ECI itself, returning ErrNotInitialized for a satellite
+// sgp4init hasn't run on and the errors of PropagateAtChecked. Unlike ECIToLookAngles, which puts the
+// observer on a sphere, it places the station on the ellipsoid, up to 21 km lower near the poles.
+func ObserverLookAngles(sat *Satellite, observer LatLong, altKm float64, t time.Time) (LookAngles, error) {
+	if sat.init == "" {
+		return LookAngles{}, ErrNotInitialized
+	}
+	pos, vel, err := PropagateAtChecked(sat, t)
+	if err != nil {
+		return LookAngles{}, err
+	}
+	return ECIToLookAnglesV2(pos, vel, observer, altKm, jdayFromTime(t)), nil
+}
+
+// ObserverRaDec returns the topocentric right ascension in [0, 2pi) and declination in radians of the
+// satellite at time t as seen from an observer at observer (geodetic latitude and longitude in radians)
+// and altKm above the WGS84 ellipsoid, in the inertial frame of the propagator output. Its errors are
+// those of ObserverLookAngles.
+func ObserverRaDec(sat *Satellite, observer LatLong, altKm float64, t time.Time) (ra, dec float64, err error) {
+	if sat.init == "" {
+		return 0, 0, ErrNotInitialized
+	}
+	pos, _, err := PropagateAtChecked(sat, t)
+	if err != nil {
+		return 0, 0, err
+	}
+	ra, dec = ECIToRaDec(pos, geodeticECI(observer, altKm, jdayFromTime(t)))
+	return ra, dec, nil
+}
+
+// PropagateVisible propagates the satellite every step from start to end inclusive and returns the
+// states and look angles for only those samples at which it is at or above minElevationDeg as seen by
+// obs, placed on the WGS84 ellipsoid as for ObserverLookAngles. The two slices are parallel. This keeps
+// long tracking logs down to the contact periods.
+func PropagateVisible(sat Satellite, obs Observer, minElevationDeg float64, start, end time.Time, step time.Duration) ([]State, []LookAngles, error) {
+	positions, velocities, times, err := propagateRange(sat, start, end, step)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	minEl := minElevationDeg * DEG2RAD
+	var states []State
+	var looks []LookAngles
+	for i, t := range times {
+		look := ECIToLookAnglesV2(positions[i], velocities[i], obs.Coords, obs.Altitude, jdayFromTime(t))
+		if look.El < minEl {
+			continue
+		}
+		states = append(states, State{Time: t, Position: positions[i], Velocity: velocities[i]})
+		looks = append(looks, look)
+	}
+	return states, looks, nil
+}
+
+// Arcseconds per radian
+const arcsecPerRadian float64 = RAD2DEG * 3600
+
+// AngularSize returns the apparent angular diameter in arcseconds of an object physicalSizeMeters across
+// seen from rangeKm away
+func AngularSize(physicalSizeMeters, rangeKm float64) float64 {
+	return 2 * math.Atan(physicalSizeMeters/2000/rangeKm) * arcsecPerRadian
+}
+
+// ObserverAngularSize returns the apparent angular diameter in arcseconds of the satellite, taken to be
+// physicalSizeMeters across, as seen by obs at time t
+func ObserverAngularSize(sat Satellite, obs Observer, physicalSizeMeters float64, t time.Time) (float64, error) {
+	look, err := obs.lookAngles(sat, t)
+	if err != nil {
+		return 0, err
+	}
+	return AngularSize(physicalSizeMeters, look.Rg), nil
+}
+
+// ObserverProvider returns where an observer is at time t and its velocity over the ground in km/s as
+// east, north and up components (X, Y and Z). It lets look angles and range rates be computed from a
+// moving platform such as a ship or aircraft.
+type ObserverProvider func(t time.Time) (obs Observer, velocity Vector3)
+
+// FixedObserver returns an ObserverProvider for a ground station that doesn't move
+func FixedObserver(obs Observer) ObserverProvider {
+	return func(time.Time) (Observer, Vector3) {
+		return obs, Vector3{}
+	}
+}
+
+// Returns the inertial velocity in km/s of an observer at obsECI moving at enuVelocity over the ground,
+// combining the Earth's rotation with the platform's motion
+func (obs Observer) eciVelocity(obsECI, enuVelocity Vector3, jday float64) Vector3 {
+	theta := math.Mod(ThetaG_JD(jday)+obs.Coords.Longitude, TWOPI)
+	sinLat, cosLat := math.Sin(obs.Coords.Latitude), math.Cos(obs.Coords.Latitude)
+	sinTheta, cosTheta := math.Sin(theta), math.Cos(theta)
+
+	east := Vector3{X: -sinTheta, Y: cosTheta}
+	north := Vector3{X: -sinLat * cosTheta, Y: -sinLat * sinTheta, Z: cosLat}
+	up := Vector3{X: cosLat * cosTheta, Y: cosLat * sinTheta, Z: sinLat}
+	platform := east.scale(enuVelocity.X).add(north.scale(enuVelocity.Y)).add(up.scale(enuVelocity.Z))
+
+	rotation := Vector3{X: -earthRotationRate * obsECI.Y, Y: earthRotationRate * obsECI.X}
+	return rotation.add(platform)
+}
+
+// PlatformLookAngles returns the look angles and range rate from the observer given by provider to the
+// satellite at time t, with the observer on the WGS84 ellipsoid. The range rate accounts for both the
+// Earth's rotation and the platform's own motion, so it gives the Doppler shift seen by a moving
+// receiver; for a fixed observer the result is that of ECIToLookAnglesV2.
+func PlatformLookAngles(sat Satellite, provider ObserverProvider, t time.Time) (LookAngles, error) {
+	pos, vel, err := propagateTime(sat, t)
+	if err != nil {
+		return LookAngles{}, err
+	}
+	obs, enuVelocity := provider(t)
+	jday := jdayFromTime(t)
+
+	obsPos := obs.eci(jday)
+	return lookAnglesWithRate(pos, vel, obsPos, obs.eciVelocity(obsPos, enuVelocity, jday), obs.Coords, jday), nil
+}
+
+// ApparentAngularRate returns how fast the satellite moves across obs's sky at time t, in degrees per
+// second: the magnitude of the angular velocity of the line of sight relative to the local horizon,
+// which is what an alt-az mount has to track and what sets the length of a streak in an exposure. It
+// combines the azimuth and elevation rates, weighting the azimuth rate by the cosine of the elevation.
+// obs is placed on the WGS84 ellipsoid, as for ObserverLookAngles.
+func ApparentAngularRate(sat Satellite, obs Observer, t time.Time) (degPerSec float64, err error) {
+	pos, vel, err := propagateTime(sat, t)
+	if err != nil {
+		return 0, err
+	}
+	jday := jdayFromTime(t)
+	gmst := gstime(jday)
+
+	// Work in Earth fixed coordinates, where the observer is at rest
+	satPos, satVel := eciToECEFState(pos, vel, gmst)
+	rho := satPos.sub(ECIToECEF(obs.eci(jday), gmst))
+	return rho.cross(satVel).norm() / rho.dot(rho) * RAD2DEG, nil
+}
+
+// Speed of light in km/s
+const speedOfLightKmS = 299792.458
+
+// DopplerShift returns the offset in Hz of the frequency received from a transmitter at frequencyHz
+// moving at rangeRate km/s, which is negative while approaching. A receding transmitter, with a
+// positive range rate, lowers the received frequency. Relativistic terms are ignored, being below a
+// part in 10^9 at orbital speeds.
+func DopplerShift(rangeRate, frequencyHz float64) float64 {
+	return -rangeRate / speedOfLightKmS * frequencyHz
+}
+
+// StationRange holds the distance in km from a station to a satellite and its rate of change in km/s,
+// positive when receding
+type StationRange struct {
+	RangeKm, RangeRateKmS float64
+}
+
+// MultiStationRangeRate returns the range and range rate from each of stations to the satellite at time
+// t, in the same order, from a single propagation so that the values are synchronized. The stations are
+// placed on the WGS84 ellipsoid, so the geometry between them holds for TDOA and FDOA work.
+func MultiStationRangeRate(sat Satellite, stations []Observer, t time.Time) ([]StationRange, error) {
+	pos, vel, err := propagateTime(sat, t)
+	if err != nil {
+		return nil, err
+	}
+	jday := jdayFromTime(t)
+
+	ranges := make([]StationRange, len(stations))
+	for i, obs := range stations {
+		look := ECIToLookAnglesV2(pos, vel, obs.Coords, obs.Altitude, jday)
+		ranges[i] = StationRange{RangeKm: look.Rg, RangeRateKmS: look.Rr}
+	}
+	return ranges, nil
+}
@@ -0,0 +1,400 @@
+package satellite
+
+import (
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("observer", func() {
+	iss := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+	epoch := time.Date(2008, 9, 20, 12, 25, 40, 0, time.UTC)
+	obs := Observer{Coords: LatLong{Latitude: 45 * DEG2RAD, Longitude: 10 * DEG2RAD}}
+
+	Describe("PropagateVisible", func() {
+		It("should keep only the samples above the mask", func() {
+			states, looks, err := PropagateVisible(iss, obs, 10, epoch, epoch.Add(24*time.Hour), time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(states).NotTo(BeEmpty())
+			Expect(states).To(HaveLen(len(looks)))
+			Expect(len(states)).To(BeNumerically("<", 24*60/10))
+
+			for i, s := range states {
+				Expect(looks[i].El).To(BeNumerically(">=", 10*DEG2RAD))
+				look, err := obs.lookAngles(iss, s.Time)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(look).To(Equal(looks[i]))
+			}
+		})
+
+		It("should match ObserverLookAngles for a station far from the equator", func() {
+			north := Observer{Coords: LatLong{Latitude: 62 * DEG2RAD, Longitude: 25 * DEG2RAD}, Altitude: 0.2}
+			states, looks, err := PropagateVisible(iss, north, 10, epoch, epoch.Add(24*time.Hour), time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(states).NotTo(BeEmpty())
+
+			for i, s := range states {
+				look, err := ObserverLookAngles(&iss, north.Coords, north.Altitude, s.Time)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(looks[i].El).To(BeNumerically("~", look.El, 1e-9))
+				Expect(looks[i].Rg).To(BeNumerically("~", look.Rg, 1e-6))
+				station := ECEFToECI(LLAToECEF(north.Coords, north.Altitude), ThetaG_JD(jdayFromTime(s.Time)))
+				Expect(looks[i].Rg).To(BeNumerically("~", s.Position.sub(station).norm(), 1e-6))
+			}
+		})
+	})
+	Describe("AngularSize", func() {
+		It("should give about 52 arcseconds for the ISS's 109 m truss at 430 km", func() {
+			Expect(AngularSize(109, 430)).To(BeNumerically("~", 52.3, 0.1))
+		})
+
+		It("should use the slant range to the satellite", func() {
+			t := epoch.Add(10 * time.Minute)
+			size, err := ObserverAngularSize(iss, obs, 109, t)
+			Expect(err).NotTo(HaveOccurred())
+			look, err := obs.lookAngles(iss, t)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(size).To(Equal(AngularSize(109, look.Rg)))
+		})
+	})
+	Describe("Residuals", func() {
+		It("should be zero for measurements generated from the same element set", func() {
+			var meas []AzElRangeMeas
+			for m := 0; m < 5; m++ {
+				t := epoch.Add(time.Duration(m) * time.Minute)
+				look, err := obs.lookAngles(iss, t)
+				Expect(err).NotTo(HaveOccurred())
+				meas = append(meas, AzElRangeMeas{Time: t, Az: look.Az, El: look.El, RangeKm: look.Rg})
+			}
+
+			residuals, err := Residuals(iss, obs, meas)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(residuals).To(HaveLen(5))
+			for i, r := range residuals {
+				Expect(r).To(Equal(AzElRangeResidual{Time: meas[i].Time}))
+			}
+		})
+
+		It("should measure from the station's position on the ellipsoid", func() {
+			// The range a station far from the equator would measure to the propagated position
+			north := Observer{Coords: LatLong{Latitude: 62 * DEG2RAD, Longitude: 25 * DEG2RAD}, Altitude: 0.2}
+			var meas []AzElRangeMeas
+			for m := 0; m < 60; m += 7 {
+				t := epoch.Add(time.Duration(m) * time.Minute)
+				pos, _ := PropagateAt(&iss, t)
+				jday := jdayFromTime(t)
+				station := ECEFToECI(LLAToECEF(north.Coords, north.Altitude), ThetaG_JD(jday))
+				look := topocentricLookAngles(pos, station, north.Coords, jday)
+				meas = append(meas, AzElRangeMeas{Time: t, Az: look.Az, El: look.El, RangeKm: look.Rg})
+			}
+
+			residuals, err := Residuals(iss, north, meas)
+			Expect(err).NotTo(HaveOccurred())
+			for _, r := range residuals {
+				Expect(r.Az).To(BeNumerically("~", 0, 1e-9))
+				Expect(r.El).To(BeNumerically("~", 0, 1e-9))
+				Expect(r.RangeKm).To(BeNumerically("~", 0, 1e-6))
+			}
+		})
+
+		It("should wrap azimuth residuals across north", func() {
+			t := epoch
+			look, err := obs.lookAngles(iss, t)
+			Expect(err).NotTo(HaveOccurred())
+			meas := AzElRangeMeas{Time: t, Az: look.Az + TWOPI - 0.01, El: look.El + 0.02, RangeKm: look.Rg - 3}
+
+			residuals, err := Residuals(iss, obs, []AzElRangeMeas{meas})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(residuals[0].Az).To(BeNumerically("~", -0.01, 1e-9))
+			Expect(residuals[0].El).To(BeNumerically("~", 0.02, 1e-9))
+			Expect(residuals[0].RangeKm).To(BeNumerically("~", -3, 1e-9))
+		})
+	})
+	Describe("PlatformLookAngles", func() {
+		t := epoch.Add(10 * time.Minute)
+
+		It("should match fixed look angles and the derivative of range for a fixed observer", func() {
+			look, err := PlatformLookAngles(iss, FixedObserver(obs), t)
+			Expect(err).NotTo(HaveOccurred())
+			fixed, err := obs.lookAngles(iss, t)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(look).To(Equal(fixed))
+			station := ECEFToECI(LLAToECEF(obs.Coords, obs.Altitude), ThetaG_JD(jdayFromTime(t)))
+			pos, _ := PropagateAt(&iss, t)
+			Expect(look.Rg).To(BeNumerically("~", pos.sub(station).norm(), 1e-6))
+
+			before, err := obs.lookAngles(iss, t.Add(-time.Second))
+			Expect(err).NotTo(HaveOccurred())
+			after, err := obs.lookAngles(iss, t.Add(time.Second))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(look.Rr).To(BeNumerically("~", (after.Rg-before.Rg)/2, 1e-3))
+		})
+
+		It("should include the platform's own motion in the range rate", func() {
+			// An aircraft flying east at 250 m/s, starting over the fixed observer, along its parallel of latitude
+			cruise := LLAToECEF(obs.Coords, 10)
+			parallelRadius := math.Hypot(cruise.X, cruise.Y)
+			aircraft := func(at time.Time) (Observer, Vector3) {
+				moved := obs
+				moved.Altitude = 10
+				moved.Coords.Longitude += 0.25 * at.Sub(t).Seconds() / parallelRadius
+				return moved, Vector3{X: 0.25}
+			}
+
+			look, err := PlatformLookAngles(iss, aircraft, t)
+			Expect(err).NotTo(HaveOccurred())
+
+			rangeAt := func(at time.Time) float64 {
+				o, _ := aircraft(at)
+				look, err := o.lookAngles(iss, at)
+				Expect(err).NotTo(HaveOccurred())
+				return look.Rg
+			}
+			Expect(look.Rr).To(BeNumerically("~", (rangeAt(t.Add(time.Second))-rangeAt(t.Add(-time.Second)))/2, 1e-3))
+		})
+	})
+	Describe("MultiStationRangeRate", func() {
+		It("should match the single station range and range rate for each station", func() {
+			t := epoch.Add(10 * time.Minute)
+			other := Observer{Coords: LatLong{Latitude: -33.9 * DEG2RAD, Longitude: 18.4 * DEG2RAD}, Altitude: 0.1}
+			ranges, err := MultiStationRangeRate(iss, []Observer{obs, other}, t)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ranges).To(HaveLen(2))
+
+			for i, station := range []Observer{obs, other} {
+				look, err := PlatformLookAngles(iss, FixedObserver(station), t)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ranges[i].RangeKm).To(BeNumerically("~", look.Rg, 1e-9))
+				Expect(ranges[i].RangeRateKmS).To(BeNumerically("~", look.Rr, 1e-12))
+			}
+		})
+
+		It("should measure from the stations' positions on the ellipsoid", func() {
+			t := epoch.Add(10 * time.Minute)
+			stations := []Observer{
+				{Coords: LatLong{Latitude: 62 * DEG2RAD, Longitude: 25 * DEG2RAD}, Altitude: 0.2},
+				{Coords: LatLong{Latitude: -70 * DEG2RAD, Longitude: -60 * DEG2RAD}},
+			}
+			ranges, err := MultiStationRangeRate(iss, stations, t)
+			Expect(err).NotTo(HaveOccurred())
+
+			pos, _ := PropagateAt(&iss, t)
+			for i, station := range stations {
+				stationECI := ECEFToECI(LLAToECEF(station.Coords, station.Altitude), ThetaG_JD(jdayFromTime(t)))
+				Expect(ranges[i].RangeKm).To(BeNumerically("~", pos.sub(stationECI).norm(), 1e-6))
+			}
+		})
+	})
+	Describe("ECIToLookAnglesV2", func() {
+		It("should add the derivative of the range to the look angles", func() {
+			t := epoch.Add(3 * time.Hour)
+			pos, vel, err := propagateTime(iss, t)
+			Expect(err).NotTo(HaveOccurred())
+			jday := jdayFromTime(t)
+
+			look := ECIToLookAnglesV2(pos, vel, obs.Coords, obs.Altitude, jday)
+			plain := ECIToLookAngles(pos, obs.Coords, obs.Altitude, jday)
+			geodetic := topocentricLookAngles(pos, ECEFToECI(LLAToECEF(obs.Coords, obs.Altitude), ThetaG_JD(jday)), obs.Coords, jday)
+			Expect([]float64{look.Az, look.El, look.Rg}).To(Equal([]float64{geodetic.Az, geodetic.El, geodetic.Rg}))
+
+			before, err := obs.lookAngles(iss, t.Add(-time.Second))
+			Expect(err).NotTo(HaveOccurred())
+			after, err := obs.lookAngles(iss, t.Add(time.Second))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(look.Rr).To(BeNumerically("~", (after.Rg-before.Rg)/2, 1e-3))
+			Expect(plain.Rr).To(BeZero())
+		})
+	})
+	Describe("DopplerShift", func() {
+		It("should lower the frequency of a receding transmitter and raise an approaching one", func() {
+			Expect(DopplerShift(7, 437e6)).To(BeNumerically("~", -10204, 1))
+			Expect(DopplerShift(-7, 437e6)).To(BeNumerically("~", 10204, 1))
+			Expect(DopplerShift(0, 437e6)).To(BeZero())
+		})
+	})
+	Describe("ApparentAngularRate", func() {
+		// Unit vector towards given look angles in east, north, up coordinates
+		pointing := func(look LookAngles) Vector3 {
+			return Vector3{X: math.Cos(look.El) * math.Sin(look.Az), Y: math.Cos(look.El) * math.Cos(look.Az), Z: math.Sin(look.El)}
+		}
+
+		It("should match the change in pointing across the sky", func() {
+			passes, err := PredictPasses(&iss, obs.Coords, obs.Altitude, epoch, epoch.Add(24*time.Hour), 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(passes).NotTo(BeEmpty())
+
+			for _, p := range passes {
+				for _, t := range []time.Time{p.AOS.Add(time.Minute), p.MaxElevationTime} {
+					rate, err := ApparentAngularRate(iss, obs, t)
+					Expect(err).NotTo(HaveOccurred())
+
+					before, err := obs.lookAngles(iss, t.Add(-500*time.Millisecond))
+					Expect(err).NotTo(HaveOccurred())
+					after, err := obs.lookAngles(iss, t.Add(500*time.Millisecond))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(rate).To(BeNumerically("~", angleBetween(pointing(before), pointing(after))*RAD2DEG, 1e-3))
+				}
+			}
+		})
+
+		It("should follow ObserverLookAngles for a station far from the equator", func() {
+			north := Observer{Coords: LatLong{Latitude: 62 * DEG2RAD, Longitude: 25 * DEG2RAD}, Altitude: 0.2}
+			passes, err := PredictPasses(&iss, north.Coords, north.Altitude, epoch, epoch.Add(24*time.Hour), 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(passes).NotTo(BeEmpty())
+
+			for _, p := range passes {
+				t := p.MaxElevationTime
+				rate, err := ApparentAngularRate(iss, north, t)
+				Expect(err).NotTo(HaveOccurred())
+				before, err := ObserverLookAngles(&iss, north.Coords, north.Altitude, t.Add(-500*time.Millisecond))
+				Expect(err).NotTo(HaveOccurred())
+				after, err := ObserverLookAngles(&iss, north.Coords, north.Altitude, t.Add(500*time.Millisecond))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(rate).To(BeNumerically("~", angleBetween(pointing(before), pointing(after))*RAD2DEG, 1e-4))
+			}
+		})
+
+		It("should be fastest at culmination", func() {
+			passes, err := PredictPasses(&iss, obs.Coords, obs.Altitude, epoch, epoch.Add(24*time.Hour), 10)
+			Expect(err).NotTo(HaveOccurred())
+			p := passes[len(passes)-1]
+			top, err := ApparentAngularRate(iss, obs, p.MaxElevationTime)
+			Expect(err).NotTo(HaveOccurred())
+			low, err := ApparentAngularRate(iss, obs, p.AOS)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(top).To(BeNumerically(">", low))
+		})
+	})
+
+	Describe("ObserverLookAngles", func() {
+		It("should do the whole chain from propagation to look angles", func() {
+			for t := epoch; t.Before(epoch.Add(24 * time.Hour)); t = t.Add(7 * time.Minute) {
+				look, err := ObserverLookAngles(&iss, obs.Coords, obs.Altitude, t)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(look.Az).To(BeNumerically(">=", 0))
+				Expect(look.Az).To(BeNumerically("<", TWOPI))
+
+				pos, _ := PropagateAt(&iss, t)
+				gmst := ThetaG_JD(jdayFromTime(t))
+				station := ECEFToECI(LLAToECEF(obs.Coords, obs.Altitude), gmst)
+				up := ECEFToECI(LLAToECEF(obs.Coords, obs.Altitude+1).sub(LLAToECEF(obs.Coords, obs.Altitude)), gmst)
+				Expect(look.Rg).To(BeNumerically("~", pos.sub(station).norm(), 1e-9))
+				Expect(math.Sin(look.El)).To(BeNumerically("~", pos.sub(station).unit().dot(up), 1e-9))
+
+				before, _ := ObserverLookAngles(&iss, obs.Coords, obs.Altitude, t.Add(-time.Second))
+				after, _ := ObserverLookAngles(&iss, obs.Coords, obs.Altitude, t.Add(time.Second))
+				Expect(look.Rr).To(BeNumerically("~", (after.Rg-before.Rg)/2, 0.001))
+			}
+		})
+
+		It("should place the observer on the WGS84 ellipsoid rather than a sphere", func() {
+			pole := LatLong{Latitude: 89 * DEG2RAD}
+			look, err := ObserverLookAngles(&iss, pole, 0, epoch)
+			Expect(err).NotTo(HaveOccurred())
+			pos, _ := PropagateAt(&iss, epoch)
+			Expect(look.Rg).NotTo(BeNumerically("~", ECIToLookAngles(pos, pole, 0, jdayFromTime(epoch)).Rg, 1))
+			Expect(look.Rg).To(BeNumerically("~", pos.sub(ECEFToECI(LLAToECEF(pole, 0), GMSTFromTime(epoch))).norm(), 1e-3))
+		})
+
+		It("should report satellites that can't be propagated", func() {
+			_, err := ObserverLookAngles(&Satellite{}, obs.Coords, 0, epoch)
+			Expect(err).To(Equal(ErrNotInitialized))
+			decayed := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0  10000-1 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+			_, err = ObserverLookAngles(&decayed, obs.Coords, 0, epoch.Add(20*24*time.Hour))
+			Expect(err).To(Equal(ErrDecayed))
+		})
+	})
+
+	Describe("PassRADecTrack", func() {
+		It("should sample the pass every step and agree with ObserverRaDec", func() {
+			passes, err := PredictPasses(&iss, obs.Coords, obs.Altitude, epoch, epoch.Add(24*time.Hour), 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(passes).NotTo(BeEmpty())
+
+			for _, step := range []time.Duration{time.Second, 7 * time.Second, time.Minute} {
+				pass := passes[0]
+				track, err := PassRADecTrack(iss, obs, pass, step)
+				Expect(err).NotTo(HaveOccurred())
+
+				// Every whole step from AOS, and LOS itself unless it falls on one
+				want := int(pass.LOS.Sub(pass.AOS)/step) + 1
+				if pass.LOS.Sub(pass.AOS)%step != 0 {
+					want++
+				}
+				Expect(track).To(HaveLen(want))
+				Expect(track[0].T).To(Equal(pass.AOS))
+				Expect(track[len(track)-1].T).To(Equal(pass.LOS))
+
+				for _, sample := range track {
+					ra, dec, err := ObserverRaDec(&iss, obs.Coords, obs.Altitude, sample.T)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(sample.RA).To(BeNumerically("~", ra, 1e-12))
+					Expect(sample.Dec).To(BeNumerically("~", dec, 1e-12))
+				}
+			}
+		})
+	})
+
+	Describe("ECIToRaDec", func() {
+		It("should put the right ascension in the right quadrant", func() {
+			for _, c := range []struct {
+				rho Vector3
+				ra  float64
+			}{
+				{Vector3{X: 1, Y: 1}, math.Pi / 4},
+				{Vector3{X: -1, Y: 1}, 3 * math.Pi / 4},
+				{Vector3{X: -1, Y: -1}, 5 * math.Pi / 4},
+				{Vector3{X: 1, Y: -1}, 7 * math.Pi / 4},
+			} {
+				obsECI := Vector3{X: 6000, Y: -1000, Z: 2000}
+				ra, dec := ECIToRaDec(obsECI.add(c.rho.scale(500)), obsECI)
+				Expect(ra).To(BeNumerically("~", c.ra, 1e-12))
+				Expect(ra).To(BeNumerically("<", TWOPI))
+				Expect(dec).To(BeNumerically("~", 0, 1e-12))
+			}
+		})
+
+		It("should keep the right ascension below 2pi just south of the x axis", func() {
+			ra, _ := ECIToRaDec(Vector3{X: 1, Y: -1e-20}, Vector3{})
+			Expect(ra).To(BeNumerically(">=", 0))
+			Expect(ra).To(BeNumerically("<", TWOPI))
+		})
+
+		It("should handle directions along the poles", func() {
+			ra, dec := ECIToRaDec(Vector3{Z: 8000}, Vector3{Z: 6357})
+			Expect(ra).To(BeZero())
+			Expect(dec).To(Equal(math.Pi / 2))
+			_, dec = ECIToRaDec(Vector3{Z: 6357}, Vector3{Z: 8000})
+			Expect(dec).To(Equal(-math.Pi / 2))
+		})
+	})
+
+	Describe("ObserverRaDec", func() {
+		It("should agree with the look angles through the hour angle", func() {
+			for t := epoch; t.Before(epoch.Add(6 * time.Hour)); t = t.Add(11 * time.Minute) {
+				ra, dec, err := ObserverRaDec(&iss, obs.Coords, obs.Altitude, t)
+				Expect(err).NotTo(HaveOccurred())
+				look, err := ObserverLookAngles(&iss, obs.Coords, obs.Altitude, t)
+				Expect(err).NotTo(HaveOccurred())
+
+				pos, _ := PropagateAt(&iss, t)
+				wantRA, wantDec := ECIToRaDec(pos, ECEFToECI(LLAToECEF(obs.Coords, obs.Altitude), ThetaG_JD(jdayFromTime(t))))
+				Expect(ra).To(BeNumerically("~", wantRA, 1e-12))
+				Expect(dec).To(BeNumerically("~", wantDec, 1e-12))
+
+				hourAngle := ThetaG_JD(jdayFromTime(t)) + obs.Coords.Longitude - ra
+				lat := obs.Coords.Latitude
+				sinEl := math.Sin(lat)*math.Sin(dec) + math.Cos(lat)*math.Cos(dec)*math.Cos(hourAngle)
+				Expect(math.Asin(sinEl)).To(BeNumerically("~", look.El, 1e-9))
+			}
+		})
+
+		It("should report satellites that can't be propagated", func() {
+			_, _, err := ObserverRaDec(&Satellite{}, obs.Coords, 0, epoch)
+			Expect(err).To(Equal(ErrNotInitialized))
+		})
+	})
+})
@@ -0,0 +1,135 @@
+package satellite
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var ErrInvalidOMM = errors.New("invalid OMM")
+
+// The fields of a CCSDS Orbit Mean-Elements Message in the JSON form published by Space-Track and
+// CelesTrak that describe an SGP4 element set
+type ommMessage struct {
+	ObjectName     string    `json:"OBJECT_NAME"`
+	ObjectID       string    `json:"OBJECT_ID"`
+	Epoch          string    `json:"EPOCH"`
+	MeanMotion     ommNumber `json:"MEAN_MOTION"`
+	Eccentricity   ommNumber `json:"ECCENTRICITY"`
+	Inclination    ommNumber `json:"INCLINATION"`
+	RAAN           ommNumber `json:"RA_OF_ASC_NODE"`
+	ArgPerigee     ommNumber `json:"ARG_OF_PERICENTER"`
+	MeanAnomaly    ommNumber `json:"MEAN_ANOMALY"`
+	EphemerisType  ommNumber `json:"EPHEMERIS_TYPE"`
+	Classification string    `json:"CLASSIFICATION_TYPE"`
+	NoradCatID     ommNumber `json:"NORAD_CAT_ID"`
+	ElementSetNo   ommNumber `json:"ELEMENT_SET_NO"`
+	RevAtEpoch     ommNumber `json:"REV_AT_EPOCH"`
+	Bstar          ommNumber `json:"BSTAR"`
+	MeanMotionDot  ommNumber `json:"MEAN_MOTION_DOT"`
+	MeanMotionDDot ommNumber `json:"MEAN_MOTION_DDOT"`
+}
+
+// A number in an OMM, which Space-Track quotes as a string and CelesTrak doesn't
+type ommNumber float64
+
+func (n *ommNumber) UnmarshalJSON(data []byte) error {
+	s := string(bytes.Trim(data, `"`))
+	if s == "" || s == "null" {
+		*n = 0
+		return nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidOMM, "malformed number %s", data)
+	}
+	*n = ommNumber(f)
+	return nil
+}
+
+// Layouts of the OMM epoch, which is UTC whether or not it ends in Z
+var ommEpochLayouts = []string{"2006-01-02T15:04:05.999999999Z07:00", "2006-01-02T15:04:05.999999999"}
+
+// ParseOMMJSON converts a single OMM in JSON into an initialized Satellite like TLEToSatV2. The mean
+// motion is in revolutions per day and angles in degrees, as in a TLE, and the epoch is an ISO 8601 UTC
+// timestamp. Numbers may be quoted, as Space-Track does. The name and catalog fields are kept for ToTLE;
+// Line1 and Line2 are left empty. It returns an error wrapping ErrInvalidOMM for malformed JSON or epochs,
+// ErrInvalidElements for elements ElementsToSat rejects and ErrUnknownGravity for an unknown gravConst.
+func ParseOMMJSON(data []byte, gravConst Gravity) (*Satellite, error) {
+	var msg ommMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return nil, errors.Wrapf(ErrInvalidOMM, "%v", err)
+	}
+	return msg.toSatellite(gravConst)
+}
+
+// ParseOMMJSONSet converts a JSON array of OMMs, as returned by Space-Track and CelesTrak queries, into
+// initialized Satellites, failing on the first message ParseOMMJSON would reject
+func ParseOMMJSONSet(data []byte, gravConst Gravity) ([]*Satellite, error) {
+	var msgs []ommMessage
+	if err := json.Unmarshal(data, &msgs); err != nil {
+		return nil, errors.Wrapf(ErrInvalidOMM, "%v", err)
+	}
+	sats := make([]*Satellite, len(msgs))
+	for i, msg := range msgs {
+		sat, err := msg.toSatellite(gravConst)
+		if err != nil {
+			return nil, errors.Wrapf(err, "message %d", i)
+		}
+		sats[i] = sat
+	}
+	return sats, nil
+}
+
+// Initializes a Satellite from the message's elements
+func (msg ommMessage) toSatellite(gravConst Gravity) (*Satellite, error) {
+	var epoch time.Time
+	var err error
+	for _, layout := range ommEpochLayouts {
+		if epoch, err = time.Parse(layout, msg.Epoch); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidOMM, "malformed epoch %q", msg.Epoch)
+	}
+
+	sat, err := ElementsToSat(Elements{
+		SatNum:         int64(msg.NoradCatID),
+		Epoch:          epoch,
+		Inclination:    float64(msg.Inclination),
+		RAAN:           float64(msg.RAAN),
+		Eccentricity:   float64(msg.Eccentricity),
+		ArgPerigee:     float64(msg.ArgPerigee),
+		MeanAnomaly:    float64(msg.MeanAnomaly),
+		MeanMotion:     float64(msg.MeanMotion),
+		Bstar:          float64(msg.Bstar),
+		MeanMotionDot:  float64(msg.MeanMotionDot),
+		MeanMotionDDot: float64(msg.MeanMotionDDot),
+	}, gravConst)
+	if err != nil {
+		return nil, err
+	}
+
+	sat.Name = msg.ObjectName
+	if msg.Classification != "" {
+		sat.classification = msg.Classification[0]
+	}
+	sat.intldesg = ommToIntlDesignator(msg.ObjectID)
+	sat.ephtype = int64(msg.EphemerisType)
+	sat.elnum = int64(msg.ElementSetNo)
+	sat.revnum = int64(msg.RevAtEpoch)
+	return &sat, nil
+}
+
+// Converts an OMM object ID such as 1998-067A into the TLE's international designator 98067A
+func ommToIntlDesignator(id string) string {
+	if len(id) > 5 && id[4] == '-' {
+		return id[2:4] + id[5:]
+	}
+	return strings.TrimSpace(id)
+}
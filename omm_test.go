@@ -0,0 +1,73 @@
+package satellite
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/pkg/errors"
+)
+
+var _ = Describe("OMM", func() {
+	line1 := "1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927"
+	line2 := "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537"
+	iss := TLEToSat(line1, line2, "wgs72")
+
+	celestrak := `{"OBJECT_NAME":"ISS (ZARYA)","OBJECT_ID":"1998-067A","EPOCH":"2008-09-20T12:25:40.104192",
+		"MEAN_MOTION":15.72125391,"ECCENTRICITY":0.0006703,"INCLINATION":51.6416,"RA_OF_ASC_NODE":247.4627,
+		"ARG_OF_PERICENTER":130.536,"MEAN_ANOMALY":325.0288,"EPHEMERIS_TYPE":0,"CLASSIFICATION_TYPE":"U",
+		"NORAD_CAT_ID":25544,"ELEMENT_SET_NO":292,"REV_AT_EPOCH":56353,"BSTAR":-1.1606e-5,
+		"MEAN_MOTION_DOT":-2.182e-5,"MEAN_MOTION_DDOT":0}`
+	spacetrack := `{"OBJECT_NAME":"ISS (ZARYA)","OBJECT_ID":"1998-067A","EPOCH":"2008-09-20T12:25:40.104192",
+		"MEAN_MOTION":"15.72125391","ECCENTRICITY":"0.00067030","INCLINATION":"51.6416","RA_OF_ASC_NODE":"247.4627",
+		"ARG_OF_PERICENTER":"130.5360","MEAN_ANOMALY":"325.0288","EPHEMERIS_TYPE":"0","CLASSIFICATION_TYPE":"U",
+		"NORAD_CAT_ID":"25544","ELEMENT_SET_NO":"292","REV_AT_EPOCH":"56353","BSTAR":"-0.000011606",
+		"MEAN_MOTION_DOT":"-0.00002182","MEAN_MOTION_DDOT":"0"}`
+
+	It("should propagate an OMM like the equivalent TLE", func() {
+		for _, data := range []string{celestrak, spacetrack} {
+			sat, err := ParseOMMJSON([]byte(data), "wgs72")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sat.Name).To(Equal("ISS (ZARYA)"))
+
+			for _, t := range []time.Time{time.Date(2008, 9, 20, 12, 25, 40, 0, time.UTC), time.Date(2008, 9, 22, 3, 0, 0, 0, time.UTC)} {
+				pos, vel := PropagateAt(sat, t)
+				wantPos, wantVel := PropagateAt(&iss, t)
+				Expect(pos.sub(wantPos).norm()).To(BeNumerically("<", 1e-3))
+				Expect(vel.sub(wantVel).norm()).To(BeNumerically("<", 1e-6))
+			}
+		}
+	})
+
+	It("should keep the catalog fields so the TLE can be reproduced", func() {
+		sat, err := ParseOMMJSON([]byte(celestrak), "wgs72")
+		Expect(err).NotTo(HaveOccurred())
+		l1, l2, err := sat.ToTLE()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(l1).To(Equal(line1))
+		Expect(l2).To(Equal(line2))
+	})
+
+	It("should parse an array of messages", func() {
+		sats, err := ParseOMMJSONSet([]byte("["+celestrak+","+spacetrack+"]"), "wgs72")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sats).To(HaveLen(2))
+		Expect(sats[1].SatNum()).To(Equal(int64(25544)))
+	})
+
+	It("should reject malformed messages", func() {
+		for _, data := range []string{
+			`{"EPOCH":"20 September 2008","MEAN_MOTION":15.7}`,
+			`{"EPOCH":"2008-09-20T12:25:40","MEAN_MOTION":"fast"}`,
+			`[1, 2]`,
+		} {
+			_, err := ParseOMMJSON([]byte(data), "wgs72")
+			Expect(errors.Cause(err)).To(Equal(ErrInvalidOMM))
+		}
+		_, err := ParseOMMJSON([]byte(`{"EPOCH":"2008-09-20T12:25:40Z","MEAN_MOTION":0}`), "wgs72")
+		Expect(errors.Cause(err)).To(Equal(ErrInvalidElements))
+		_, err = ParseOMMJSONSet([]byte("["+celestrak+`,{"EPOCH":"x"}]`), "wgs72")
+		Expect(errors.Cause(err)).To(Equal(ErrInvalidOMM))
+	})
+})
@@ -0,0 +1,229 @@
+package satellite
+
+import (
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Rotation rate of the Earth in radians per second
+const earthRotationRate float64 = 7.2921158553e-5
+
+var ErrInvalidRepeatCycle = errors.New("repeat cycle must span a positive number of days and orbits")
+var ErrInvalidAltitude = errors.New("altitude must be above the Earth's surface")
+
+// Converts a duration in minutes into a time.Duration
+func minutesToDuration(minutes float64) time.Duration {
+	return time.Duration(minutes * float64(time.Minute))
+}
+
+// OrbitNormal returns the unit angular momentum vector r×v of the orbit described by an ECI
+// position and velocity. It is perpendicular to the instantaneous orbit plane.
+func OrbitNormal(pos, vel Vector3) Vector3 {
+	return pos.cross(vel).unit()
+}
+
+// OrbitNormalAt returns the unit angular momentum vector of the satellite's orbit at time t in ECI coordinates
+func (sat *Satellite) OrbitNormalAt(t time.Time) (Vector3, error) {
+	pos, vel, err := propagateTime(*sat, t)
+	if err != nil {
+		return Vector3{}, err
+	}
+	return OrbitNormal(pos, vel), nil
+}
+
+// PlaneAngle returns the dihedral angle in degrees between the orbit planes of two satellites at
+// time t, measured between their orbit normals. Co-planar satellites moving in the same direction
+// give 0 and those moving in opposite directions give 180.
+func PlaneAngle(a, b Satellite, t time.Time) (float64, error) {
+	normalA, err := a.OrbitNormalAt(t)
+	if err != nil {
+		return 0, err
+	}
+	normalB, err := b.OrbitNormalAt(t)
+	if err != nil {
+		return 0, err
+	}
+	return angleBetween(normalA, normalB) * RAD2DEG, nil
+}
+
+// GeocentricRadiusKm returns the satellite's distance in km from the Earth's center at time t.
+// Unlike the altitude returned by ECIToLLA it involves no ellipsoid, which makes it the quantity
+// to use in vis-viva and energy checks.
+func (sat *Satellite) GeocentricRadiusKm(t time.Time) (float64, error) {
+	pos, _, err := propagateTime(*sat, t)
+	if err != nil {
+		return 0, err
+	}
+	return pos.norm(), nil
+}
+
+// SemiMajorAxisKm returns the mean semi-major axis in km implied by the mean motion recovered by
+// sgp4init, using the Earth radius and gravitational parameter of the satellite's gravity model.
+// It and Period, ApogeeKm and PerigeeKm need a satellite initialized by TLEToSat or similar.
+func (sat *Satellite) SemiMajorAxisKm() float64 {
+	return math.Pow(sat.whichconst.xke/sat.no, 2.0/3.0) * sat.whichconst.radiusearthkm
+}
+
+// Period returns the Keplerian period 2π/n of the mean motion recovered by sgp4init. AnomalisticPeriod
+// and NodalPeriod add the J2 secular rates and time apsis and node passages better.
+func (sat *Satellite) Period() time.Duration {
+	return minutesToDuration(TWOPI / sat.no)
+}
+
+// ApogeeKm returns the mean apogee altitude above the gravity model's Earth radius in km
+func (sat *Satellite) ApogeeKm() float64 {
+	return sat.SemiMajorAxisKm()*(1+sat.ecco) - sat.whichconst.radiusearthkm
+}
+
+// PerigeeKm returns the mean perigee altitude above the gravity model's Earth radius in km
+func (sat *Satellite) PerigeeKm() float64 {
+	return sat.SemiMajorAxisKm()*(1-sat.ecco) - sat.whichconst.radiusearthkm
+}
+
+// AnomalisticPeriod returns the time between successive perigee passages. It is derived from the
+// secular rate of the mean anomaly, including the J2 correction, computed by sgp4init, and is the
+// period to use for apsis timing.
+//
+// It differs from the Keplerian period 2π/n implied by the TLE's mean motion because J2 speeds
+// up or slows down the motion of the mean anomaly depending on inclination.
+func (sat *Satellite) AnomalisticPeriod() time.Duration {
+	return minutesToDuration(TWOPI / sat.mdot)
+}
+
+// NodalPeriod returns the time between successive ascending node crossings, also known as the
+// draconic period. It adds the J2 secular rotation of the argument of perigee to the mean anomaly
+// rate, and is the period that governs ground track repeat cycles.
+func (sat *Satellite) NodalPeriod() time.Duration {
+	return minutesToDuration(TWOPI / (sat.mdot + sat.argpdot))
+}
+
+// RAANDriftDegPerDay returns the secular drift of the right ascension of the ascending node caused by J2,
+// in degrees per day. It is negative for prograde orbits, whose node regresses westward, and about
+// +0.9856 for a sun-synchronous orbit.
+func (sat *Satellite) RAANDriftDegPerDay() float64 {
+	return sat.nodedot * 1440.0 * RAD2DEG
+}
+
+// Rate in degrees per day at which the mean sun's right ascension advances, 360° per tropical year
+const meanSunRateDegPerDay = 360 / 365.24219
+
+// MeanLocalTimeDriftMinPerDay returns how fast the mean local solar time of the ascending node is
+// changing around time t, in minutes per day: the node's drift in right ascension less the mean sun's
+// 0.9856°/day, at four minutes of time per degree. It is near zero for a sun-synchronous orbit on
+// station, and its size tells operators how soon an inclination maneuver is needed. The node's drift is
+// measured from the osculating right ascension half a nodal period either side of t, which averages out
+// the short-period perturbations but keeps drag and the other secular effects.
+func (sat *Satellite) MeanLocalTimeDriftMinPerDay(t time.Time) (float64, error) {
+	half := sat.NodalPeriod() / 2
+	before, err := sat.OsculatingElementsAt(t.Add(-half))
+	if err != nil {
+		return 0, err
+	}
+	after, err := sat.OsculatingElementsAt(t.Add(half))
+	if err != nil {
+		return 0, err
+	}
+	raanRate := wrapPi(after.RAAN-before.RAAN) * RAD2DEG / (2 * half).Hours() * 24
+	return (raanRate - meanSunRateDegPerDay) * 4, nil
+}
+
+// Returns the eastward shift in radians of the ascending node's longitude over one nodal period.
+// It is negative because the Earth turns beneath the orbit faster than the node regresses.
+func (sat *Satellite) nodeLongitudeShift() float64 {
+	nodalRegression := sat.nodedot / 60.0
+	return -(earthRotationRate - nodalRegression) * sat.NodalPeriod().Seconds()
+}
+
+// NodeLongitudeShiftDeg returns the eastward shift in degrees of the ascending node's longitude from one
+// orbit to the next, −(Earth rotation rate − nodal regression rate) × nodal period. It is negative as the
+// track walks west, and slightly larger in magnitude than the nodal period times the Earth rotation rate
+// alone for prograde orbits, whose node regresses westward too.
+func (sat *Satellite) NodeLongitudeShiftDeg() float64 {
+	return sat.nodeLongitudeShift() * RAD2DEG
+}
+
+// GroundTrackDriftRate returns how fast the satellite's ground track walks, in km per day at the
+// equator, relative to the ideal track of a repeat cycle of targetOrbits nodal periods in targetDays
+// days. Positive values mean the track drifts east of the reference grid. The rate combines the
+// nodal period and nodal regression from the J2 secular rates with the Earth's rotation.
+func GroundTrackDriftRate(sat Satellite, targetDays, targetOrbits int) (kmPerDay float64, err error) {
+	if targetDays <= 0 || targetOrbits <= 0 {
+		return 0, ErrInvalidRepeatCycle
+	}
+
+	ideal := -TWOPI * float64(targetDays) / float64(targetOrbits)
+	drift := sat.nodeLongitudeShift() - ideal
+	orbitsPerDay := 86400.0 / sat.NodalPeriod().Seconds()
+
+	return drift * sat.whichconst.radiusearthkm * orbitsPerDay, nil
+}
+
+// InclinationSeries samples the satellite's osculating inclination in radians every step from start to
+// end inclusive, computed from the orbit normal of the propagated state. The TLE inclination is a mean
+// element with the periodic effects of J2 averaged out; the osculating value oscillates around it twice
+// per orbit by a few hundredths of a degree in low Earth orbit, and more slowly under lunisolar effects
+// for deep space orbits. Expect a measured inclination to show the same wobble.
+func InclinationSeries(sat Satellite, start, end time.Time, step time.Duration) ([]float64, []time.Time, error) {
+	positions, velocities, times, err := propagateRange(sat, start, end, step)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	inclinations := make([]float64, len(times))
+	for i := range times {
+		inclinations[i] = math.Acos(OrbitNormal(positions[i], velocities[i]).Z)
+	}
+	return inclinations, times, nil
+}
+
+// FrozenOrbitEccentricity returns the mean eccentricity that, with the argument of perigee held at 90°,
+// freezes an orbit of the given mean altitude and inclination: the first order J3 perturbation of
+// eccentricity and argument of perigee then cancels the J2 apsidal rotation, so the perigee stays
+// over the same latitude. It uses e = -(J3/2J2)(R/a) sin i with the WGS72 constants.
+func FrozenOrbitEccentricity(altitudeKm, inclinationDeg float64) (float64, error) {
+	if altitudeKm <= 0 {
+		return 0, ErrInvalidAltitude
+	}
+	grav := getGravConst(GravityWGS72)
+	a := grav.radiusearthkm + altitudeKm
+	return -0.5 * grav.j3oj2 * grav.radiusearthkm / a * math.Sin(inclinationDeg*DEG2RAD), nil
+}
+
+// SameOrbit reports whether a and b are within posToleranceKm of each other when both are propagated to
+// time at. It flags element sets that describe the same object even when their catalog numbers or epochs
+// differ; pick at near both epochs, since the positions grow apart as propagation errors accumulate.
+func SameOrbit(a, b Satellite, posToleranceKm float64, at time.Time) (bool, error) {
+	posA, _, err := propagateTime(a, at)
+	if err != nil {
+		return false, err
+	}
+	posB, _, err := propagateTime(b, at)
+	if err != nil {
+		return false, err
+	}
+	return posA.sub(posB).norm() <= posToleranceKm, nil
+}
+
+// RevolutionsBetween returns the number of ascending node crossings between t1 and t2, counted exactly
+// by finding each time the propagated position crosses the equatorial plane heading north rather than
+// estimated from the mean motion. The position is sampled eight times per nodal period, so only orbits
+// that stay far enough from equatorial for each crossing to be distinct are counted reliably.
+func (sat *Satellite) RevolutionsBetween(t1, t2 time.Time) (int, error) {
+	_, transitions, err := findTransitions(t1, t2, sat.NodalPeriod()/8, func(t time.Time) (bool, error) {
+		pos, _, err := propagateTime(*sat, t)
+		return pos.Z > 0, err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, tr := range transitions {
+		if tr.rising {
+			count++
+		}
+	}
+	return count, nil
+}
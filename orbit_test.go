@@ -0,0 +1,280 @@
+package satellite
+
+import (
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("orbit", func() {
+	iss := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+	keplerian := minutesToDuration(TWOPI / iss.no)
+
+	Describe("periods", func() {
+		It("should stay within a fraction of a percent of the keplerian period", func() {
+			Expect(iss.AnomalisticPeriod().Seconds()).To(BeNumerically("~", keplerian.Seconds(), keplerian.Seconds()*0.005))
+			Expect(iss.NodalPeriod().Seconds()).To(BeNumerically("~", keplerian.Seconds(), keplerian.Seconds()*0.005))
+		})
+
+		It("should give a shorter nodal period when the perigee advances", func() {
+			// At 51.6 degrees inclination J2 advances the argument of perigee
+			Expect(iss.argpdot).To(BeNumerically(">", 0))
+			Expect(iss.NodalPeriod()).To(BeNumerically("<", iss.AnomalisticPeriod()))
+			Expect(iss.NodalPeriod()).To(BeNumerically("~", 91*time.Minute+30*time.Second, time.Minute))
+		})
+	})
+	Describe("RAANDriftDegPerDay", func() {
+		It("should regress the ISS node by about 5 degrees per day", func() {
+			Expect(iss.RAANDriftDegPerDay()).To(BeNumerically("~", -5.0, 0.2))
+		})
+
+		It("should advance the node of a sun-synchronous orbit with the sun", func() {
+			sso := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  98.6000 247.4627 0006703 130.5360 325.0288 14.20000000563537", "wgs72")
+			Expect(sso.RAANDriftDegPerDay()).To(BeNumerically("~", 0.9856, 0.03))
+		})
+	})
+
+	Describe("NodeLongitudeShiftDeg", func() {
+		It("should walk the ISS node about 23 degrees west per orbit, more than the Earth's rotation alone", func() {
+			rotationOnly := -earthRotationRate * iss.NodalPeriod().Seconds() * RAD2DEG
+			Expect(iss.NodeLongitudeShiftDeg()).To(BeNumerically("~", -23.26, 0.05))
+			Expect(iss.NodeLongitudeShiftDeg()).To(BeNumerically("<", rotationOnly))
+		})
+	})
+
+	Describe("InclinationSeries", func() {
+		It("should oscillate around the mean TLE inclination", func() {
+			epoch := time.Date(2008, 9, 20, 12, 25, 40, 0, time.UTC)
+			incs, times, err := InclinationSeries(iss, epoch, epoch.Add(iss.NodalPeriod()), time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(incs).To(HaveLen(len(times)))
+
+			lo, hi, sum := incs[0], incs[0], 0.0
+			for _, inc := range incs {
+				lo, hi, sum = math.Min(lo, inc), math.Max(hi, inc), sum+inc
+			}
+			Expect(sum / float64(len(incs)) * RAD2DEG).To(BeNumerically("~", 51.6416, 0.05))
+			Expect((hi - lo) * RAD2DEG).To(BeNumerically(">", 0.005))
+			Expect((hi - lo) * RAD2DEG).To(BeNumerically("<", 0.2))
+		})
+	})
+	Describe("FrozenOrbitEccentricity", func() {
+		It("should give about 0.001 for a sun-synchronous orbit at 800 km", func() {
+			e, err := FrozenOrbitEccentricity(800, 98.6)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(e).To(BeNumerically("~", 0.00103, 0.00002))
+		})
+
+		It("should vanish for an equatorial orbit and reject altitudes below the surface", func() {
+			Expect(FrozenOrbitEccentricity(800, 0)).To(BeZero())
+			_, err := FrozenOrbitEccentricity(-10, 98.6)
+			Expect(err).To(Equal(ErrInvalidAltitude))
+		})
+	})
+	Describe("SameOrbit", func() {
+		epoch := time.Date(2008, 9, 20, 12, 25, 40, 0, time.UTC)
+
+		It("should match a copy under another catalog number", func() {
+			duplicate := TLEToSat("1 99999U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 99999  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+			Expect(SameOrbit(iss, duplicate, 0.001, epoch)).To(BeTrue())
+		})
+
+		It("should tell apart orbits that differ only in mean anomaly", func() {
+			ahead := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 326.0288 15.72125391563537", "wgs72")
+			Expect(SameOrbit(iss, ahead, 10, epoch)).To(BeFalse())
+			Expect(SameOrbit(iss, ahead, 200, epoch)).To(BeTrue())
+		})
+	})
+	Describe("RevolutionsBetween", func() {
+		epoch := time.Date(2008, 9, 20, 12, 25, 40, 0, time.UTC)
+
+		It("should count the ISS's daily revolutions", func() {
+			revs, err := iss.RevolutionsBetween(epoch, epoch.Add(24*time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(revs).To(BeNumerically("~", 15.7, 1))
+		})
+
+		It("should count one crossing per nodal period", func() {
+			revs, err := iss.RevolutionsBetween(epoch, epoch.Add(10*iss.NodalPeriod()))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(revs).To(Equal(10))
+		})
+
+		It("should reject a reversed range", func() {
+			_, err := iss.RevolutionsBetween(epoch, epoch.Add(-time.Hour))
+			Expect(err).To(Equal(ErrInvalidTimeRange))
+		})
+	})
+	Describe("orbit geometry", func() {
+		It("should give the ISS's period and apsis altitudes", func() {
+			Expect(iss.Period()).To(Equal(keplerian))
+			Expect(iss.Period().Minutes()).To(BeNumerically("~", 91.6, 0.2))
+			Expect(iss.PerigeeKm()).To(BeNumerically("~", 349, 2))
+			Expect(iss.ApogeeKm()).To(BeNumerically("~", 358, 2))
+			Expect(iss.ApogeeKm() - iss.PerigeeKm()).To(BeNumerically("~", 2*iss.SemiMajorAxisKm()*0.0006703, 1e-9))
+		})
+
+		It("should use the radius of the satellite's gravity model", func() {
+			line1 := "1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927"
+			line2 := "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537"
+			wgs84 := TLEToSat(line1, line2, GravityWGS84)
+			Expect(wgs84.PerigeeKm()).To(BeNumerically("~", wgs84.SemiMajorAxisKm()*(1-0.0006703)-6378.137, 1e-9))
+			Expect(wgs84.PerigeeKm()).NotTo(Equal(iss.PerigeeKm()))
+		})
+	})
+	Describe("MeanLocalTimeDriftMinPerDay", func() {
+		epoch := time.Date(2008, 9, 20, 12, 25, 40, 0, time.UTC)
+
+		It("should follow the secular node drift relative to the mean sun", func() {
+			drift, err := iss.MeanLocalTimeDriftMinPerDay(epoch.Add(time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(drift).To(BeNumerically("~", (iss.RAANDriftDegPerDay()-0.9856)*4, 0.2))
+		})
+
+		It("should be near zero for a sun-synchronous orbit", func() {
+			sso, err := GenerateWalker(98.19, 1, 1, 0, 700, 0.001, epoch, GravityWGS72)
+			Expect(err).NotTo(HaveOccurred())
+			drift, err := sso[0].MeanLocalTimeDriftMinPerDay(epoch.Add(time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(math.Abs(drift)).To(BeNumerically("<", 0.1))
+		})
+	})
+
+	Describe("OrbitNormal", func() {
+		It("should give +Z for an equatorial prograde orbit", func() {
+			Expect(OrbitNormal(Vector3{X: 7000}, Vector3{Y: 7.5}).sub(Vector3{Z: 1}).norm()).To(BeNumerically("<", 1e-15))
+			Expect(OrbitNormal(Vector3{X: -4000, Y: 5000}, Vector3{X: -3, Y: -4}).sub(Vector3{Z: 1}).norm()).To(BeNumerically("<", 1e-15))
+
+			equatorial := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544   0.0000 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+			normal, err := equatorial.OrbitNormalAt(iss.Epoch().Add(time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(normal.Z).To(BeNumerically("~", 1, 1e-12))
+		})
+
+		It("should be a unit vector at the TLE inclination from +Z", func() {
+			for t := iss.Epoch(); t.Before(iss.Epoch().Add(3 * time.Hour)); t = t.Add(13 * time.Minute) {
+				normal, err := iss.OrbitNormalAt(t)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(normal.norm()).To(BeNumerically("~", 1, 1e-12))
+				Expect(angleBetween(normal, Vector3{Z: 1}) * RAD2DEG).To(BeNumerically("~", 51.6416, 0.05))
+
+				pos, vel := PropagateAt(&iss, t)
+				Expect(normal).To(Equal(OrbitNormal(pos, vel)))
+			}
+		})
+	})
+
+	Describe("PlaneAngle", func() {
+		polar := func(raan string) Satellite {
+			return TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  90.0000 "+raan+" 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+		}
+
+		It("should be zero between a satellite and itself", func() {
+			angle, err := PlaneAngle(iss, iss, iss.Epoch().Add(time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(angle).To(BeNumerically("~", 0, 1e-6))
+		})
+
+		It("should give the difference in RAAN between polar orbits", func() {
+			for _, c := range []struct {
+				raan  string
+				delta float64
+			}{
+				{"257.4627", 10},
+				{"292.4627", 45},
+				{"337.4627", 90},
+			} {
+				angle, err := PlaneAngle(polar("247.4627"), polar(c.raan), iss.Epoch().Add(time.Hour))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(angle).To(BeNumerically("~", c.delta, 0.05))
+			}
+		})
+
+		It("should be symmetric", func() {
+			a, b := iss, polar("300.0000")
+			for t := iss.Epoch(); t.Before(iss.Epoch().Add(3 * time.Hour)); t = t.Add(17 * time.Minute) {
+				ab, err := PlaneAngle(a, b, t)
+				Expect(err).NotTo(HaveOccurred())
+				ba, err := PlaneAngle(b, a, t)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ab).To(Equal(ba))
+			}
+		})
+	})
+
+	Describe("GroundTrackDriftRate", func() {
+		// A sun-synchronous orbit in the 16 day, 233 orbit repeat cycle of Landsat 8, at a few mean motions
+		landsat := func(meanMotion string) Satellite {
+			return TLEToSat("1 39084U 13008A   14001.00000000  .00000000  00000-0  00000-0 0  9990", "2 39084  98.2000 100.0000 0001000  90.0000 270.0000 "+meanMotion+" 10000", "wgs72")
+		}
+
+		It("should be near zero on the repeat cycle", func() {
+			onCycle := landsat("14.57100000")
+			Expect(onCycle.RAANDriftDegPerDay()).To(BeNumerically("~", 0.9856, 0.005))
+			drift, err := GroundTrackDriftRate(onCycle, 16, 233)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(math.Abs(drift)).To(BeNumerically("<", 2))
+		})
+
+		It("should follow from the nodal period and the node's drift", func() {
+			for _, mm := range []string{"14.56000000", "14.57100000", "14.58000000"} {
+				sat := landsat(mm)
+				nodalPeriod := sat.NodalPeriod().Seconds()
+				earthDegPerSec := earthRotationRate * RAD2DEG
+				nodeShift := -(earthDegPerSec - sat.RAANDriftDegPerDay()/86400) * nodalPeriod
+				ideal := -360.0 * 16 / 233
+				want := (nodeShift - ideal) * DEG2RAD * sat.whichconst.radiusearthkm * 86400 / nodalPeriod
+
+				drift, err := GroundTrackDriftRate(sat, 16, 233)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(drift).To(BeNumerically("~", want, 1e-6))
+			}
+		})
+
+		It("should drift east when the satellite orbits faster than the cycle and west when slower", func() {
+			fast, err := GroundTrackDriftRate(landsat("14.58000000"), 16, 233)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fast).To(BeNumerically("~", 24, 5))
+			slow, err := GroundTrackDriftRate(landsat("14.56000000"), 16, 233)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(slow).To(BeNumerically("~", -31, 5))
+		})
+
+		It("should reject an empty repeat cycle", func() {
+			_, err := GroundTrackDriftRate(iss, 0, 233)
+			Expect(err).To(Equal(ErrInvalidRepeatCycle))
+			_, err = GroundTrackDriftRate(iss, 16, 0)
+			Expect(err).To(Equal(ErrInvalidRepeatCycle))
+		})
+	})
+
+	Describe("GeocentricRadiusKm", func() {
+		It("should be the length of the propagated position", func() {
+			for t := iss.Epoch(); t.Before(iss.Epoch().Add(3 * time.Hour)); t = t.Add(11 * time.Minute) {
+				radius, err := iss.GeocentricRadiusKm(t)
+				Expect(err).NotTo(HaveOccurred())
+				pos, _ := PropagateAt(&iss, t)
+				Expect(radius).To(Equal(pos.norm()))
+			}
+		})
+
+		It("should stay between the perigee and apogee radii over an orbit", func() {
+			eccentric := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0106703 130.5360 325.0288 15.02125391563537", "wgs72")
+			re := eccentric.whichconst.radiusearthkm
+			perigee, apogee := eccentric.PerigeeKm()+re, eccentric.ApogeeKm()+re
+
+			lowest, highest := math.Inf(1), 0.0
+			for t := eccentric.Epoch(); t.Before(eccentric.Epoch().Add(eccentric.Period())); t = t.Add(10 * time.Second) {
+				radius, err := eccentric.GeocentricRadiusKm(t)
+				Expect(err).NotTo(HaveOccurred())
+				lowest, highest = math.Min(lowest, radius), math.Max(highest, radius)
+			}
+			// The apsides are mean values; the short-period J2 terms move the osculating radius by several km
+			Expect(lowest).To(BeNumerically(">", perigee-10))
+			Expect(highest).To(BeNumerically("<", apogee+10))
+			Expect(highest - lowest).To(BeNumerically(">", 0.9*(apogee-perigee)))
+		})
+	})
+})
@@ -0,0 +1,134 @@
+package satellite
+
+import (
+	"math"
+	"time"
+)
+
+// OsculatingElements holds the classical Keplerian elements of the two body orbit tangent to a state
+// vector, with the semi-major axis in km and angles in radians. ElementRates fills the same fields with
+// rates of change per second.
+type OsculatingElements struct {
+	SemiMajorAxis float64
+	Eccentricity  float64
+	Inclination   float64
+	RAAN          float64 // Right ascension of the ascending node
+	ArgPerigee    float64
+	TrueAnomaly   float64
+	MeanAnomaly   float64
+}
+
+// StateToElements converts an ECI position (km) and velocity (km/s) into osculating elements for a body
+// with gravitational parameter mu (km³/s²). Angles are in [0, 2π). The node is undefined for equatorial
+// orbits and the perigee for circular ones; those angles then come out as zero and the anomalies are
+// measured from the node or the x axis instead. Only elliptical orbits are supported.
+// Reference: Vallado, Fundamentals of Astrodynamics and Applications, algorithm 9 (rv2coe).
+func StateToElements(pos, vel Vector3, mu float64) (el OsculatingElements) {
+	const small = 1e-10
+
+	r, v := pos.norm(), vel.norm()
+	h := pos.cross(vel)
+	node := Vector3{Z: 1}.cross(h)
+	ecc := pos.scale(v*v - mu/r).sub(vel.scale(pos.dot(vel))).scale(1 / mu)
+
+	el.SemiMajorAxis = 1 / (2/r - v*v/mu)
+	el.Eccentricity = ecc.norm()
+	el.Inclination = math.Acos(h.Z / h.norm())
+
+	equatorial := node.norm() < small*h.norm()
+	circular := el.Eccentricity < small
+	if !equatorial {
+		el.RAAN = math.Atan2(node.Y, node.X)
+	} else {
+		node = Vector3{X: 1}
+	}
+
+	// Reference direction in the orbit plane from which the true anomaly is measured
+	ref := ecc
+	if circular {
+		ref = node
+	} else {
+		el.ArgPerigee = angleBetween(node, ecc)
+		if ecc.dot(h.cross(node)) < 0 {
+			el.ArgPerigee = TWOPI - el.ArgPerigee
+		}
+	}
+	el.TrueAnomaly = angleBetween(ref, pos)
+	if pos.dot(h.cross(ref)) < 0 {
+		el.TrueAnomaly = TWOPI - el.TrueAnomaly
+	}
+
+	e := el.Eccentricity
+	E := 2 * math.Atan(math.Sqrt((1-e)/(1+e))*math.Tan(el.TrueAnomaly/2))
+	el.MeanAnomaly = math.Mod(E-e*math.Sin(E)+TWOPI, TWOPI)
+	el.RAAN = math.Mod(el.RAAN+TWOPI, TWOPI)
+	return el
+}
+
+// OsculatingElementsAt returns the osculating elements of the satellite's state at time t, using its
+// gravity model's mu
+func (sat *Satellite) OsculatingElementsAt(t time.Time) (OsculatingElements, error) {
+	pos, vel, err := propagateTime(*sat, t)
+	if err != nil {
+		return OsculatingElements{}, err
+	}
+	return StateToElements(pos, vel, sat.whichconst.mu), nil
+}
+
+// ElementRates returns the time derivative of each osculating element at time t, in km/s for the
+// semi-major axis, per second for the eccentricity and radians per second for the angles.
+//
+// They are central finite differences of the elements computed at t ± h, with h one ten-thousandth of
+// the anomalistic period (about half a second in low Earth orbit). That balances the truncation error,
+// which grows with h² and the short-period harmonics of the perturbations, against the roundoff in
+// elements that are only good to about 1e-12 relative. Expect rates accurate to around one part in 10^6
+// of their typical size; angle rates are unreliable wherever the angle itself is undefined, such as the
+// argument of perigee of a near circular orbit.
+func ElementRates(sat Satellite, t time.Time) (OsculatingElements, error) {
+	if sat.init == "" {
+		return OsculatingElements{}, ErrNotInitialized
+	}
+	hMin := 1e-4 * TWOPI / sat.mdot
+	tsince := minutesSinceEpoch(&sat, t)
+
+	before, err := sat.elementsAtMinutes(tsince - hMin)
+	if err != nil {
+		return OsculatingElements{}, err
+	}
+	after, err := sat.elementsAtMinutes(tsince + hMin)
+	if err != nil {
+		return OsculatingElements{}, err
+	}
+
+	span := 2 * hMin * 60
+	angleRate := func(a, b float64) float64 { return wrapPi(b-a) / span }
+	return OsculatingElements{
+		SemiMajorAxis: (after.SemiMajorAxis - before.SemiMajorAxis) / span,
+		Eccentricity:  (after.Eccentricity - before.Eccentricity) / span,
+		Inclination:   (after.Inclination - before.Inclination) / span,
+		RAAN:          angleRate(before.RAAN, after.RAAN),
+		ArgPerigee:    angleRate(before.ArgPerigee, after.ArgPerigee),
+		TrueAnomaly:   angleRate(before.TrueAnomaly, after.TrueAnomaly),
+		MeanAnomaly:   angleRate(before.MeanAnomaly, after.MeanAnomaly),
+	}, nil
+}
+
+// Returns the osculating elements tsince minutes after epoch
+func (sat *Satellite) elementsAtMinutes(tsince float64) (OsculatingElements, error) {
+	pos, vel, err := propagateMinutes(sat, tsince)
+	if err != nil {
+		return OsculatingElements{}, err
+	}
+	return StateToElements(pos, vel, sat.whichconst.mu), nil
+}
+
+// OsculatingEccentricity returns the eccentricity of the two body orbit tangent to the satellite's state
+// at time t. It differs from the mean eccentricity of the TLE, which SGP4 averages the short-period
+// perturbations out of: mostly through J2, the eccentricity vector is displaced twice per orbit by an
+// amount of order J2·(R/a)², about 0.001 in low Earth orbit. For a near circular orbit that displacement
+// dominates the mean value, so the osculating eccentricity stays well above the TLE's and tracking data
+// fitted with two body orbits shows a larger, varying eccentricity.
+func OsculatingEccentricity(sat Satellite, t time.Time) (float64, error) {
+	el, err := sat.OsculatingElementsAt(t)
+	return el.Eccentricity, err
+}
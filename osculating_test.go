@@ -0,0 +1,80 @@
+package satellite
+
+import (
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("osculating elements", func() {
+	iss := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+	epoch := time.Date(2008, 9, 20, 12, 25, 40, 0, time.UTC)
+
+	Describe("StateToElements", func() {
+		// Vallado, Fundamentals of Astrodynamics and Applications, example 2-5
+		It("should match the textbook example", func() {
+			pos := Vector3{X: 6524.834, Y: 6862.875, Z: 6448.296}
+			vel := Vector3{X: 4.901327, Y: 5.533756, Z: -1.976341}
+			el := StateToElements(pos, vel, 398600.4418)
+
+			Expect(el.SemiMajorAxis).To(BeNumerically("~", 36127.343, 0.01))
+			Expect(el.Eccentricity).To(BeNumerically("~", 0.832853, 1e-6))
+			Expect(el.Inclination * RAD2DEG).To(BeNumerically("~", 87.870, 0.001))
+			Expect(el.RAAN * RAD2DEG).To(BeNumerically("~", 227.89, 0.01))
+			Expect(el.ArgPerigee * RAD2DEG).To(BeNumerically("~", 53.38, 0.01))
+			Expect(el.TrueAnomaly * RAD2DEG).To(BeNumerically("~", 92.335, 0.001))
+		})
+
+		It("should stay close to the mean elements of a TLE", func() {
+			el, err := iss.OsculatingElementsAt(epoch)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(el.Inclination * RAD2DEG).To(BeNumerically("~", 51.6416, 0.1))
+			Expect(el.RAAN * RAD2DEG).To(BeNumerically("~", 247.4627, 0.1))
+			Expect(el.SemiMajorAxis).To(BeNumerically("~", iss.SemiMajorAxisKm(), 15))
+		})
+	})
+
+	Describe("ElementRates", func() {
+		It("should advance the argument of latitude at the mean motion and bound the node rate", func() {
+			rates, err := ElementRates(iss, epoch)
+			Expect(err).NotTo(HaveOccurred())
+			// The perigee of a near circular orbit is poorly defined, so the anomaly and argument of
+			// perigee rates trade off against each other, but their sum follows the 15.72 revolutions per day
+			Expect(rates.TrueAnomaly + rates.ArgPerigee).To(BeNumerically("~", 15.72125391*TWOPI/86400, 2e-5))
+			// J2 short-period terms dominate the instantaneous node rate, but it stays within a few
+			// times the secular regression of about 5 degrees per day
+			Expect(math.Abs(rates.RAAN * 86400 * RAD2DEG)).To(BeNumerically("<", 50))
+		})
+
+		It("should match a difference of elements over a longer step", func() {
+			rates, err := ElementRates(iss, epoch)
+			Expect(err).NotTo(HaveOccurred())
+			before, err := iss.OsculatingElementsAt(epoch.Add(-5 * time.Second))
+			Expect(err).NotTo(HaveOccurred())
+			after, err := iss.OsculatingElementsAt(epoch.Add(5 * time.Second))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rates.SemiMajorAxis).To(BeNumerically("~", (after.SemiMajorAxis-before.SemiMajorAxis)/10, 1e-3))
+			Expect(rates.Inclination).To(BeNumerically("~", (after.Inclination-before.Inclination)/10, 1e-9))
+		})
+
+		It("should reject an uninitialized satellite", func() {
+			_, err := ElementRates(Satellite{}, epoch)
+			Expect(err).To(Equal(ErrNotInitialized))
+		})
+	})
+	Describe("OsculatingEccentricity", func() {
+		It("should oscillate twice per orbit above the mean eccentricity of a near circular orbit", func() {
+			lowest, highest := math.Inf(1), math.Inf(-1)
+			for m := 0; m < 92; m += 2 {
+				e, err := OsculatingEccentricity(iss, epoch.Add(time.Duration(m)*time.Minute))
+				Expect(err).NotTo(HaveOccurred())
+				lowest, highest = math.Min(lowest, e), math.Max(highest, e)
+			}
+			Expect(lowest).To(BeNumerically(">", iss.ecco))
+			Expect(highest - lowest).To(BeNumerically(">", 0.0005))
+			Expect(highest).To(BeNumerically("<", iss.ecco+0.003))
+		})
+	})
+})
@@ -0,0 +1,170 @@
+package satellite
+
+import (
+	"math"
+	"time"
+)
+
+// Pass describes a single window during which a satellite is visible to an observer
+type Pass struct {
+	AOS          time.Time // Acquisition of signal, when the satellite rises above the observer's mask
+	LOS          time.Time // Loss of signal, when the satellite sets below the observer's mask
+	MaxElevation float64   // Highest elevation reached during the pass, in radians
+
+	MaxElevationTime time.Time // When the satellite reaches MaxElevation
+
+	// Azimuths in radians at AOS, MaxElevationTime and LOS
+	AOSAzimuth, MaxElevationAzimuth, LOSAzimuth float64
+}
+
+// Maximum elevations in degrees at which Pass.Quality moves up from "marginal" to "low", "good" and "overhead".
+// They may be changed to suit an application, before any goroutine calls Quality.
+var (
+	PassLowDeg      = 10.0
+	PassGoodDeg     = 30.0
+	PassOverheadDeg = 70.0
+)
+
+// Quality labels the pass by its maximum elevation: "overhead" above PassOverheadDeg, "good" above
+// PassGoodDeg, "low" above PassLowDeg and "marginal" otherwise
+func (p Pass) Quality() string {
+	el := p.MaxElevation * RAD2DEG
+	switch {
+	case el > PassOverheadDeg:
+		return "overhead"
+	case el > PassGoodDeg:
+		return "good"
+	case el > PassLowDeg:
+		return "low"
+	}
+	return "marginal"
+}
+
+// Score rates the pass from 0 for one that only grazes the horizon to 1 for one through the zenith,
+// in proportion to its maximum elevation
+func (p Pass) Score() float64 {
+	return math.Max(0, math.Min(1, p.MaxElevation/(math.Pi/2)))
+}
+
+// MaxPassDuration returns the longest a satellite in a circular orbit at altitudeKm can stay at or above
+// minElevationDeg as seen by a ground observer, which is the duration of a pass through the zenith. It
+// uses the WGS72 Earth radius and gravitational parameter and ignores the Earth's rotation, which
+// lengthens or shortens real passes by a few percent in low Earth orbit.
+func MaxPassDuration(altitudeKm, minElevationDeg float64) time.Duration {
+	grav := getGravConst(GravityWGS72)
+	a := grav.radiusearthkm + altitudeKm
+	el := minElevationDeg * DEG2RAD
+
+	// Earth central angle between the observer and the point where the satellite crosses the mask
+	lambda := math.Acos(grav.radiusearthkm*math.Cos(el)/a) - el
+	period := TWOPI * math.Sqrt(a*a*a/grav.mu)
+
+	return time.Duration(lambda / math.Pi * period * float64(time.Second))
+}
+
+// MutualVisibility returns the windows between start and end during which the satellite is at or above
+// minElevationDeg as seen from both stationA and stationB, the relay opportunities for a bent pipe link.
+// The stations are placed on the WGS84 ellipsoid as for PredictPasses.
+// A window under way at start begins at start, and one still under way at end finishes at end. The
+// elevations are sampled every step, which must be shorter than the briefest window of interest, and
+// each window edge is refined by bisection to within a millisecond. MaxElevation, MaxElevationTime and
+// the azimuths are left zero, as they differ between the stations.
+func MutualVisibility(sat Satellite, stationA, stationB Observer, minElevationDeg float64, start, end time.Time, step time.Duration) ([]Pass, error) {
+	minEl := minElevationDeg * DEG2RAD
+	initial, transitions, err := findTransitions(start, end, step, func(t time.Time) (bool, error) {
+		lookA, err := stationA.lookAngles(sat, t)
+		if err != nil {
+			return false, err
+		}
+		lookB, err := stationB.lookAngles(sat, t)
+		if err != nil {
+			return false, err
+		}
+		return lookA.El >= minEl && lookB.El >= minEl, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var passes []Pass
+	for _, in := range transitionIntervals(start, end, initial, transitions) {
+		passes = append(passes, Pass{AOS: in.start, LOS: in.end})
+	}
+	return passes, nil
+}
+
+// Interval at which PredictPasses samples the elevation, short enough not to miss a low pass in low Earth orbit
+const passStep = 20 * time.Second
+
+// PredictPasses returns the passes between start and end during which the satellite is at or above
+// minElevationDeg as seen from an observer at observer (latitude and longitude in radians) and altKm,
+// with every field of Pass filled in. The elevation is sampled every 20 seconds, AOS and LOS refined by
+// bisection to within a millisecond and the culmination by parabolic interpolation. A pass under way at
+// start begins at start, and one still under way at end finishes at end; either may culminate there.
+// No passes in the window give an empty result, not an error.
+func PredictPasses(sat *Satellite, observer LatLong, altKm float64, start, end time.Time, minElevationDeg float64) ([]Pass, error) {
+	obs := Observer{Coords: observer, Altitude: altKm}
+	minEl := minElevationDeg * DEG2RAD
+	initial, transitions, err := findTransitions(start, end, passStep, func(t time.Time) (bool, error) {
+		look, err := obs.lookAngles(*sat, t)
+		return look.El >= minEl, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var passes []Pass
+	for _, in := range transitionIntervals(start, end, initial, transitions) {
+		pass, err := obs.describePass(*sat, in)
+		if err != nil {
+			return nil, err
+		}
+		passes = append(passes, pass)
+	}
+	return passes, nil
+}
+
+// Fills in a Pass over the visibility interval in, finding the culmination and the azimuths
+func (obs Observer) describePass(sat Satellite, in interval) (Pass, error) {
+	negElevation := func(t time.Time) (float64, error) {
+		look, err := obs.lookAngles(sat, t)
+		return -look.El, err
+	}
+
+	times, err := sampleTimes(in.start, in.end, passStep)
+	if err != nil {
+		return Pass{}, err
+	}
+	best, bestVal := 0, math.Inf(1)
+	for i, t := range times {
+		v, err := negElevation(t)
+		if err != nil {
+			return Pass{}, err
+		}
+		if v < bestVal {
+			best, bestVal = i, v
+		}
+	}
+	culmination := times[best]
+	if best > 0 && best < len(times)-1 {
+		if culmination, bestVal, err = refineMinimum(times[best-1], times[best], times[best+1], bestVal, negElevation); err != nil {
+			return Pass{}, err
+		}
+	}
+
+	pass := Pass{AOS: in.start, LOS: in.end, MaxElevation: -bestVal, MaxElevationTime: culmination}
+	aos, err := obs.lookAngles(sat, in.start)
+	if err != nil {
+		return Pass{}, err
+	}
+	top, err := obs.lookAngles(sat, culmination)
+	if err != nil {
+		return Pass{}, err
+	}
+	los, err := obs.lookAngles(sat, in.end)
+	if err != nil {
+		return Pass{}, err
+	}
+	pass.AOSAzimuth, pass.MaxElevationAzimuth, pass.LOSAzimuth = aos.Az, top.Az, los.Az
+	return pass, nil
+}
@@ -0,0 +1,169 @@
+package satellite
+
+import (
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pass", func() {
+	Describe("MaxPassDuration", func() {
+		It("should give about ten minutes horizon to horizon at ISS altitude", func() {
+			Expect(MaxPassDuration(400, 0)).To(BeNumerically("~", 10*time.Minute+9*time.Second, 5*time.Second))
+		})
+
+		It("should shorten as the mask rises and lengthen with altitude", func() {
+			Expect(MaxPassDuration(400, 10)).To(BeNumerically("<", MaxPassDuration(400, 0)))
+			Expect(MaxPassDuration(800, 10)).To(BeNumerically(">", MaxPassDuration(400, 10)))
+			Expect(MaxPassDuration(400, 90)).To(BeZero())
+		})
+	})
+	Describe("Quality", func() {
+		It("should label passes by maximum elevation", func() {
+			for el, label := range map[float64]string{80: "overhead", 45: "good", 20: "low", 5: "marginal"} {
+				Expect(Pass{MaxElevation: el * DEG2RAD}.Quality()).To(Equal(label))
+			}
+		})
+
+		It("should follow overridden thresholds", func() {
+			defer func(low float64) { PassLowDeg = low }(PassLowDeg)
+			PassLowDeg = 25
+			Expect(Pass{MaxElevation: 20 * DEG2RAD}.Quality()).To(Equal("marginal"))
+		})
+
+		It("should score from zero at the horizon to one at the zenith", func() {
+			Expect(Pass{}.Score()).To(BeZero())
+			Expect(Pass{MaxElevation: 45 * DEG2RAD}.Score()).To(BeNumerically("~", 0.5, 1e-12))
+			Expect(Pass{MaxElevation: math.Pi / 2}.Score()).To(BeNumerically("~", 1, 1e-12))
+		})
+	})
+
+	Describe("MutualVisibility", func() {
+		iss := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+		epoch := time.Date(2008, 9, 20, 12, 25, 40, 0, time.UTC)
+		rome := Observer{Coords: LatLong{Latitude: 41.9 * DEG2RAD, Longitude: 12.5 * DEG2RAD}}
+		munich := Observer{Coords: LatLong{Latitude: 48.1 * DEG2RAD, Longitude: 11.6 * DEG2RAD}}
+
+		It("should find windows when both stations see the satellite", func() {
+			passes, err := MutualVisibility(iss, rome, munich, 5, epoch, epoch.Add(24*time.Hour), 30*time.Second)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(passes).NotTo(BeEmpty())
+
+			for _, p := range passes {
+				Expect(p.LOS.After(p.AOS)).To(BeTrue())
+				mid := p.AOS.Add(p.LOS.Sub(p.AOS) / 2)
+				for _, station := range []Observer{rome, munich} {
+					look, err := station.lookAngles(iss, mid)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(look.El).To(BeNumerically(">=", 5*DEG2RAD))
+				}
+			}
+		})
+
+		It("should never exceed the windows of either station alone", func() {
+			mutual, err := MutualVisibility(iss, rome, munich, 5, epoch, epoch.Add(24*time.Hour), 30*time.Second)
+			Expect(err).NotTo(HaveOccurred())
+			alone, err := MutualVisibility(iss, rome, rome, 5, epoch, epoch.Add(24*time.Hour), 30*time.Second)
+			Expect(err).NotTo(HaveOccurred())
+
+			var mutualTotal, aloneTotal time.Duration
+			for _, p := range mutual {
+				mutualTotal += p.LOS.Sub(p.AOS)
+			}
+			for _, p := range alone {
+				aloneTotal += p.LOS.Sub(p.AOS)
+			}
+			Expect(mutualTotal).To(BeNumerically("<", aloneTotal))
+		})
+
+		It("should give the passes of PredictPasses for a single station", func() {
+			alone, err := MutualVisibility(iss, munich, munich, 10, epoch, epoch.Add(24*time.Hour), 20*time.Second)
+			Expect(err).NotTo(HaveOccurred())
+			passes, err := PredictPasses(&iss, munich.Coords, munich.Altitude, epoch, epoch.Add(24*time.Hour), 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(alone).To(HaveLen(len(passes)))
+			for i, p := range passes {
+				Expect(alone[i].AOS.Sub(p.AOS)).To(BeNumerically("~", 0, 2*time.Millisecond))
+				Expect(alone[i].LOS.Sub(p.LOS)).To(BeNumerically("~", 0, 2*time.Millisecond))
+			}
+		})
+	})
+	Describe("PredictPasses", func() {
+		iss := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+		epoch := time.Date(2008, 9, 20, 12, 25, 40, 0, time.UTC)
+		site := LatLong{Latitude: 45 * DEG2RAD, Longitude: 10 * DEG2RAD}
+		obs := Observer{Coords: site}
+
+		It("should find the passes above the mask with their culminations and azimuths", func() {
+			passes, err := PredictPasses(&iss, site, 0, epoch, epoch.Add(24*time.Hour), 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(passes).NotTo(BeEmpty())
+
+			for _, p := range passes {
+				Expect(p.LOS.After(p.AOS)).To(BeTrue())
+				Expect(p.LOS.Sub(p.AOS)).To(BeNumerically("<", MaxPassDuration(340, 10)+time.Minute))
+
+				for _, edge := range []struct {
+					t  time.Time
+					az float64
+				}{{p.AOS, p.AOSAzimuth}, {p.LOS, p.LOSAzimuth}} {
+					look, err := obs.lookAngles(iss, edge.t)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(look.El * RAD2DEG).To(BeNumerically("~", 10, 0.01))
+					Expect(look.Az).To(Equal(edge.az))
+				}
+
+				Expect(p.MaxElevationTime.After(p.AOS) && p.MaxElevationTime.Before(p.LOS)).To(BeTrue())
+				for _, offset := range []time.Duration{-10 * time.Second, 10 * time.Second} {
+					look, err := obs.lookAngles(iss, p.MaxElevationTime.Add(offset))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(look.El).To(BeNumerically("<", p.MaxElevation))
+				}
+				Expect(p.MaxElevation * RAD2DEG).To(BeNumerically(">", 10))
+			}
+		})
+
+		It("should start a pass already under way at start", func() {
+			passes, err := PredictPasses(&iss, site, 0, epoch, epoch.Add(24*time.Hour), 10)
+			Expect(err).NotTo(HaveOccurred())
+			first := passes[0]
+			mid := first.AOS.Add(first.LOS.Sub(first.AOS) / 3)
+
+			inProgress, err := PredictPasses(&iss, site, 0, mid, mid.Add(time.Hour), 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(inProgress).NotTo(BeEmpty())
+			Expect(inProgress[0].AOS).To(Equal(mid))
+			Expect(inProgress[0].LOS.Sub(first.LOS)).To(BeNumerically("~", 0, 2*time.Millisecond))
+		})
+
+		It("should agree with ObserverLookAngles, both placing the station on the ellipsoid", func() {
+			north := LatLong{Latitude: 62 * DEG2RAD, Longitude: 25 * DEG2RAD}
+			passes, err := PredictPasses(&iss, north, 0.2, epoch, epoch.Add(24*time.Hour), 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(passes).NotTo(BeEmpty())
+
+			for _, p := range passes {
+				top, err := ObserverLookAngles(&iss, north, 0.2, p.MaxElevationTime)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(top.El).To(BeNumerically("~", p.MaxElevation, 1e-9))
+				Expect(top.Az).To(BeNumerically("~", p.MaxElevationAzimuth, 1e-9))
+				pos, _ := PropagateAt(&iss, p.MaxElevationTime)
+				jday := jdayFromTime(p.MaxElevationTime)
+				station := ECEFToECI(LLAToECEF(north, 0.2), ThetaG_JD(jday))
+				Expect(topocentricLookAngles(pos, station, north, jday).El).To(BeNumerically("~", p.MaxElevation, 1e-9))
+
+				los, err := ObserverLookAngles(&iss, north, 0.2, p.LOS)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(los.El).To(BeNumerically("~", 10*DEG2RAD, 1e-5))
+			}
+		})
+
+		It("should return no passes when the satellite never rises above the mask", func() {
+			passes, err := PredictPasses(&iss, LatLong{Latitude: 89 * DEG2RAD}, 0, epoch, epoch.Add(24*time.Hour), 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(passes).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,41 @@
+package satellite
+
+import (
+	"time"
+)
+
+// Propagator propagates one satellite to many times. It only caches the conversion of each time to
+// minutes since epoch, taken by a duration from a reference instant rather than through a Julian date;
+// the orbit itself is propagated by sgp4 exactly as PropagateAtChecked does, sgp4init having already
+// computed the terms that don't change with time. That saves under a tenth of the cost of a propagation,
+// so use it for the Julian date rounding it avoids rather than for speed. A Propagator is never modified
+// after NewPropagator, so it is safe for concurrent use.
+type Propagator struct {
+	sat Satellite
+
+	// A time near the epoch, and its offset in minutes from the exact epoch
+	ref       time.Time
+	refMinute float64
+}
+
+// NewPropagator returns a Propagator for a satellite initialized by sgp4init. Later changes to sat don't
+// affect it.
+func NewPropagator(sat *Satellite) *Propagator {
+	ref := timeFromJday(sat.jdsatepoch)
+	return &Propagator{
+		sat:       *sat,
+		ref:       ref,
+		refMinute: minutesSinceEpoch(sat, ref),
+	}
+}
+
+// At calculates position (km) and velocity (km/s) vectors at time t, with the errors of PropagateAtChecked.
+// The results differ from it by well under a metre, PropagateAtChecked carrying the tens of microseconds
+// of rounding in a float64 Julian date that At avoids.
+func (p *Propagator) At(t time.Time) (position, velocity Vector3, err error) {
+	tsince := p.refMinute + t.Sub(p.ref).Minutes()
+	if err := checkPropagationSpan(tsince); err != nil {
+		return Vector3{}, Vector3{}, err
+	}
+	return propagateMinutes(&p.sat, tsince)
+}
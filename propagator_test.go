@@ -0,0 +1,95 @@
+package satellite
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/pkg/errors"
+)
+
+var _ = Describe("Propagator", func() {
+	iss := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+	geo := TLEToSat("1 24208U 96044A   06177.04061740 -.00000094  00000-0  10000-3 0  1600", "2 24208   3.8536  80.0121 0026640 311.0977  48.3000  1.00778054 36119", "wgs72")
+
+	It("should match PropagateAtChecked", func() {
+		for _, sat := range []Satellite{iss, geo} {
+			p := NewPropagator(&sat)
+			epoch := sat.Epoch()
+			for t := epoch.Add(-24 * time.Hour); t.Before(epoch.Add(3 * 24 * time.Hour)); t = t.Add(17*time.Minute + 123*time.Millisecond) {
+				pos, vel, err := p.At(t)
+				Expect(err).NotTo(HaveOccurred())
+				wantPos, wantVel, _ := PropagateAtChecked(&sat, t)
+				Expect(pos.sub(wantPos).norm()).To(BeNumerically("<", 1e-3))
+				Expect(vel.sub(wantVel).norm()).To(BeNumerically("<", 1e-6))
+			}
+		}
+	})
+
+	It("should be safe for concurrent use", func() {
+		decayed := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0  10000-1 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+		p := NewPropagator(&decayed)
+		mismatches := make([]int, 8)
+		var wg sync.WaitGroup
+		for i := range mismatches {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				// Alternate between decayed and healthy times so that a shared error field would show
+				days := time.Duration(i%2*20) * 24 * time.Hour
+				for j := 0; j < 100; j++ {
+					if _, _, err := p.At(decayed.Epoch().Add(days)); (err == nil) != (i%2 == 0) {
+						mismatches[i]++
+					}
+				}
+			}(i)
+		}
+		wg.Wait()
+		Expect(mismatches).To(Equal(make([]int, 8)))
+	})
+
+	It("should report the errors PropagateAtChecked does", func() {
+		p := NewPropagator(&iss)
+		_, _, err := p.At(iss.Epoch().Add(40 * 24 * time.Hour))
+		Expect(errors.Cause(err)).To(Equal(ErrHorizonExceeded))
+
+		decayed := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0  10000-1 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+		p = NewPropagator(&decayed)
+		_, _, err = p.At(decayed.Epoch().Add(20 * 24 * time.Hour))
+		Expect(err).To(Equal(ErrDecayed))
+		_, _, err = p.At(decayed.Epoch())
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
+
+func BenchmarkPropagate(b *testing.B) {
+	sat := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+	start := time.Date(2008, 9, 20, 12, 0, 0, 0, time.UTC)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		t := start.Add(time.Duration(i%10000) * time.Minute)
+		Propagate(sat, t.Year(), int(t.Month()), t.Day(), t.Hour(), t.Minute(), t.Second())
+	}
+}
+
+func BenchmarkPropagatorAt(b *testing.B) {
+	sat := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+	start := time.Date(2008, 9, 20, 12, 0, 0, 0, time.UTC)
+	p := NewPropagator(&sat)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		p.At(start.Add(time.Duration(i%10000) * time.Minute))
+	}
+}
+
+func BenchmarkPropagateAtChecked(b *testing.B) {
+	sat := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+	start := time.Date(2008, 9, 20, 12, 0, 0, 0, time.UTC)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		PropagateAtChecked(&sat, start.Add(time.Duration(i%10000)*time.Minute))
+	}
+}
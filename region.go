@@ -0,0 +1,49 @@
+package satellite
+
+import (
+	"time"
+)
+
+// LatLongBox is a region bounded by two parallels and two meridians, with corners in radians. A box whose
+// Min longitude is greater than its Max longitude spans the antimeridian, so {Min: {0, 170°}, Max: {10°, -170°}}
+// covers the 20° of longitude either side of 180°.
+type LatLongBox struct {
+	Min, Max LatLong // South-west and north-east corners
+}
+
+// Contains reports whether ll, in radians, lies within the box, including its edges
+func (box LatLongBox) Contains(ll LatLong) bool {
+	if ll.Latitude < box.Min.Latitude || ll.Latitude > box.Max.Latitude {
+		return false
+	}
+	lon, west, east := wrapPi(ll.Longitude), wrapPi(box.Min.Longitude), wrapPi(box.Max.Longitude)
+	if west <= east {
+		return lon >= west && lon <= east
+	}
+	return lon >= west || lon <= east
+}
+
+// RegionInterval is a span of time during which a satellite's subpoint is inside a region
+type RegionInterval struct {
+	Entry, Exit time.Time
+}
+
+// RegionCrossings returns the intervals between start and end during which the satellite's geodetic
+// subpoint lies inside box. An interval already under way at start begins at start, and one still under
+// way at end finishes at end. The subpoint is sampled every step and each entry and exit is refined by
+// bisection to within a millisecond, so step must be shorter than the quickest transit of the box.
+func RegionCrossings(sat Satellite, box LatLongBox, start, end time.Time, step time.Duration) ([]RegionInterval, error) {
+	inside, transitions, err := findTransitions(start, end, step, func(t time.Time) (bool, error) {
+		ll, _, err := subpoint(sat, t)
+		return box.Contains(ll), err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var intervals []RegionInterval
+	for _, in := range transitionIntervals(start, end, inside, transitions) {
+		intervals = append(intervals, RegionInterval{Entry: in.start, Exit: in.end})
+	}
+	return intervals, nil
+}
@@ -0,0 +1,51 @@
+package satellite
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("region", func() {
+	iss := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+	epoch := time.Date(2008, 9, 20, 12, 25, 40, 0, time.UTC)
+
+	Describe("LatLongBox", func() {
+		It("should contain points across the antimeridian when it spans it", func() {
+			box := LatLongBox{Min: LatLong{Latitude: 0, Longitude: 170 * DEG2RAD}, Max: LatLong{Latitude: 10 * DEG2RAD, Longitude: -170 * DEG2RAD}}
+			Expect(box.Contains(LatLong{Latitude: 5 * DEG2RAD, Longitude: 175 * DEG2RAD})).To(BeTrue())
+			Expect(box.Contains(LatLong{Latitude: 5 * DEG2RAD, Longitude: -175 * DEG2RAD})).To(BeTrue())
+			Expect(box.Contains(LatLong{Latitude: 5 * DEG2RAD, Longitude: 0})).To(BeFalse())
+			Expect(box.Contains(LatLong{Latitude: 15 * DEG2RAD, Longitude: 175 * DEG2RAD})).To(BeFalse())
+		})
+	})
+
+	Describe("RegionCrossings", func() {
+		It("should find entry and exit times whose subpoints lie on the box edges", func() {
+			box := LatLongBox{Min: LatLong{Latitude: -60 * DEG2RAD, Longitude: 160 * DEG2RAD}, Max: LatLong{Latitude: 60 * DEG2RAD, Longitude: -160 * DEG2RAD}}
+			intervals, err := RegionCrossings(iss, box, epoch, epoch.Add(24*time.Hour), 30*time.Second)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(intervals).NotTo(BeEmpty())
+
+			for _, in := range intervals {
+				Expect(in.Exit.After(in.Entry)).To(BeTrue())
+				inside, _, err := subpoint(iss, in.Entry.Add(in.Exit.Sub(in.Entry)/2))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(box.Contains(inside)).To(BeTrue())
+				outside, _, err := subpoint(iss, in.Exit.Add(time.Second))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(box.Contains(outside)).To(BeFalse())
+			}
+		})
+
+		It("should clip an interval under way at the start of the window", func() {
+			ll, _, err := subpoint(iss, epoch)
+			Expect(err).NotTo(HaveOccurred())
+			box := LatLongBox{Min: LatLong{Latitude: ll.Latitude - 0.2, Longitude: ll.Longitude - 0.4}, Max: LatLong{Latitude: ll.Latitude + 0.2, Longitude: ll.Longitude + 0.4}}
+			intervals, err := RegionCrossings(iss, box, epoch, epoch.Add(time.Hour), 30*time.Second)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(intervals[0].Entry).To(Equal(epoch))
+		})
+	})
+})
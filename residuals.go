@@ -0,0 +1,42 @@
+package satellite
+
+import (
+	"time"
+)
+
+// AzElRangeMeas is a measurement of a satellite's direction and distance from an observer, with angles
+// in radians and range in km
+type AzElRangeMeas struct {
+	Time    time.Time
+	Az, El  float64
+	RangeKm float64
+}
+
+// AzElRangeResidual is the observed minus computed difference for an AzElRangeMeas, in the same units.
+// Azimuth residuals are wrapped into (-π, π].
+type AzElRangeResidual struct {
+	Time    time.Time
+	Az, El  float64
+	RangeKm float64
+}
+
+// Residuals propagates the satellite to the time of each measurement taken by obs and returns the
+// observed minus computed azimuth, elevation and range. Systematic trends in the residuals indicate
+// how well, and in which direction, the element set fails to match the tracking data. obs is placed
+// on the WGS84 ellipsoid, so give it the station's surveyed geodetic coordinates.
+func Residuals(sat Satellite, obs Observer, measurements []AzElRangeMeas) ([]AzElRangeResidual, error) {
+	residuals := make([]AzElRangeResidual, len(measurements))
+	for i, m := range measurements {
+		look, err := obs.lookAngles(sat, m.Time)
+		if err != nil {
+			return nil, err
+		}
+		residuals[i] = AzElRangeResidual{
+			Time:    m.Time,
+			Az:      wrapPi(m.Az - look.Az),
+			El:      m.El - look.El,
+			RangeKm: m.RangeKm - look.Rg,
+		}
+	}
+	return residuals, nil
+}
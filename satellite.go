@@ -1,15 +1,33 @@
 package satellite
 
-// Struct for holding satellite information during and before propagation
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// Struct for holding satellite information during and before propagation.
+// Once initialized, a Satellite is only read by the propagation functions, which work on a copy, so a
+// single *Satellite may be propagated from several goroutines at once. Modifying one, for example by
+// initializing it again, while another goroutine propagates it is still a data race.
 type Satellite struct {
+	Name  string `json:"OBJECT_NAME"` // Common name from the first line of a three line element set, if any
 	Line1 string `json:"TLE_LINE1"`
 	Line2 string `json:"TLE_LINE2"`
 
 	satnum int64
 
+	// Catalog fields of the TLE that SGP4 doesn't use, kept so ToTLE can reproduce them
+	classification byte
+	intldesg       string
+	ephtype        int64
+	elnum          int64
+	revnum         int64
+
 	Error      int64
 	ErrorStr   string
 	whichconst GravConst
+	gravity    Gravity
 
 	epochyr    int64
 	epochdays  float64
@@ -27,6 +45,8 @@ type Satellite struct {
 	alta  float64
 	altp  float64
 
+	noKozai float64 // Mean motion from the TLE in radians per minute, as sgp4init replaces no with its Brouwer value
+
 	method        string
 	operationmode string
 	init          string
@@ -115,3 +135,69 @@ type Satellite struct {
 	xlamo float64
 	atime float64
 }
+
+// Clone returns a copy of the satellite that shares no state with it. A Satellite holds no pointers, so
+// the copy is as cheap as assigning the struct.
+func (sat *Satellite) Clone() *Satellite {
+	c := *sat
+	return &c
+}
+
+// The JSON form of a Satellite: the element set it was parsed from and how it was initialized, from which
+// the propagator state is rebuilt
+type satelliteJSON struct {
+	Name    string  `json:"OBJECT_NAME,omitempty"`
+	Line1   string  `json:"TLE_LINE1"`
+	Line2   string  `json:"TLE_LINE2"`
+	Gravity Gravity `json:"GRAVITY,omitempty"`
+	OpsMode OpsMode `json:"OPS_MODE,omitempty"`
+}
+
+// MarshalJSON encodes the satellite as its name, TLE lines, gravity model and operation mode rather than
+// its internal state. Satellites built from elements rather than a TLE, such as by ElementsToSat, are
+// written with the lines ToTLE formats, which round their elements to the precision of a TLE.
+func (sat Satellite) MarshalJSON() ([]byte, error) {
+	line1, line2 := sat.Line1, sat.Line2
+	if line1 == "" && line2 == "" && sat.init != "" {
+		var err error
+		if line1, line2, err = sat.ToTLE(); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(satelliteJSON{
+		Name:    sat.Name,
+		Line1:   line1,
+		Line2:   line2,
+		Gravity: sat.gravity,
+		OpsMode: OpsMode(sat.operationmode),
+	})
+}
+
+// UnmarshalJSON decodes a satellite written by MarshalJSON and runs sgp4init on it again, so it propagates
+// exactly as the original did. The gravity model defaults to WGS72 and the operation mode to improved,
+// which also lets it read the element sets Space-Track returns. Malformed lines give the errors of
+// ParseTLEV2, and an operation mode other than OpsModeAFSPC or OpsModeImproved ErrUnknownOpsMode.
+func (sat *Satellite) UnmarshalJSON(data []byte) error {
+	var s satelliteJSON
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s.Gravity == "" {
+		s.Gravity = GravityWGS72
+	}
+	if s.OpsMode == "" {
+		s.OpsMode = OpsModeImproved
+	}
+	if s.OpsMode != OpsModeAFSPC && s.OpsMode != OpsModeImproved {
+		return errors.Wrapf(ErrUnknownOpsMode, "%q", s.OpsMode)
+	}
+
+	parsed, err := ParseTLEV2(s.Line1, s.Line2, s.Gravity)
+	if err != nil {
+		return err
+	}
+	initSatellite(&parsed, s.OpsMode)
+	parsed.Name = s.Name
+	*sat = parsed
+	return nil
+}
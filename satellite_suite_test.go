@@ -4,9 +4,16 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"encoding/json"
+	"fmt"
+	"math"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/pkg/errors"
 )
 
 func TestSatellite(t *testing.T) {
@@ -14,6 +21,14 @@ func TestSatellite(t *testing.T) {
 	RunSpecs(t, "Satellite Suite")
 }
 
+type recordingLogger struct {
+	messages []string
+}
+
+func (l *recordingLogger) Printf(format string, v ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprintf(format, v...))
+}
+
 type Result struct {
 	time               float64
 	position, velocity Vector3
@@ -76,6 +91,646 @@ var _ = Describe("go-satellite", func() {
 		})
 	})
 
+	Describe("TLEToSatWithOpsMode", func() {
+		line1 := "1 23599U 95029B   06171.76535463  .00085586  12891-6  12956-2 0  2905"
+		line2 := "2 23599   6.9327   0.2849 5782022 274.4436  25.2425  4.47796565123555"
+
+		It("should record the requested operation mode", func() {
+			Expect(TLEToSatWithOpsMode(line1, line2, "wgs72", OpsModeAFSPC).operationmode).To(Equal("a"))
+			Expect(TLEToSat(line1, line2, "wgs72").operationmode).To(Equal("i"))
+		})
+
+		// The sidereal time at epoch differs by ~1e-11 rad between the modes, but for a low inclination
+		// deep space orbit the AFSPC handling of the node in dpper moves the satellite by about a km.
+		It("should differ at the km level from improved mode for a low inclination deep space orbit", func() {
+			afspc := TLEToSatWithOpsMode(line1, line2, "wgs72", OpsModeAFSPC)
+			improved := TLEToSatWithOpsMode(line1, line2, "wgs72", OpsModeImproved)
+			Expect(afspc.gsto).NotTo(Equal(improved.gsto))
+			Expect(afspc.gsto).To(BeNumerically("~", improved.gsto, 1e-9))
+
+			afspcPos, _ := sgp4(&afspc, 1440)
+			improvedPos, _ := sgp4(&improved, 1440)
+			Expect(afspcPos.sub(improvedPos).norm()).To(BeNumerically("~", 1.0, 0.5))
+		})
+	})
+
+	Describe("ParseTLEV2", func() {
+		line1 := "1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927"
+		line2 := "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537"
+		badLine2 := "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.7212X391563537"
+
+		It("should match ParseTLE for a well formed element set", func() {
+			sat, err := ParseTLEV2(line1, line2, "wgs72")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sat).To(Equal(ParseTLE(line1, line2, "wgs72")))
+		})
+
+		It("should return an error naming the malformed field", func() {
+			_, err := ParseTLEV2(line1, badLine2, "wgs72")
+			Expect(errors.Cause(err)).To(Equal(ErrInvalidTLE))
+			Expect(err.Error()).To(ContainSubstring("mean motion"))
+
+			_, err = TLEToSatV2(line1, badLine2, "wgs72")
+			Expect(errors.Cause(err)).To(Equal(ErrInvalidTLE))
+		})
+
+		It("should reject a corrupt epoch year", func() {
+			for _, year := range []string{" 8", "8 ", "-8", "+8", "0x"} {
+				_, err := ParseTLEV2(line1[:18]+year+line1[20:], line2, "wgs72")
+				Expect(errors.Cause(err)).To(Equal(ErrInvalidTLE))
+				Expect(err.Error()).To(ContainSubstring("epoch year"))
+			}
+		})
+
+		It("should return an error for an unknown gravity model", func() {
+			_, err := ParseTLEV2(line1, line2, "wgs99")
+			Expect(errors.Cause(err)).To(Equal(ErrUnknownGravity))
+		})
+
+		It("should report errors from the legacy helpers through Log instead of exiting", func() {
+			logger := &recordingLogger{}
+			defer func(l Logger) { Log = l }(Log)
+			Log = logger
+
+			ParseTLE(line1, badLine2, "wgs72")
+			Expect(logger.messages).To(HaveLen(1))
+			Expect(logger.messages[0]).To(ContainSubstring("mean motion"))
+
+			Log = nil
+			Expect(func() { ParseTLE(line1, badLine2, "wgs72") }).NotTo(Panic())
+		})
+
+		It("should reject short lines instead of panicking", func() {
+			_, err := ParseTLEV2(line1, line2[:40], "wgs72")
+			Expect(errors.Cause(err)).To(Equal(ErrInvalidTLE))
+			Expect(err.Error()).To(ContainSubstring("line 2 is 40 characters long"))
+
+			_, err = ParseTLEV2("", line2, "wgs72")
+			Expect(errors.Cause(err)).To(Equal(ErrInvalidTLE))
+
+			defer func(l Logger) { Log = l }(Log)
+			Log = nil
+			Expect(func() { TLEToSat(line1[:10], "", "wgs72") }).NotTo(Panic())
+		})
+
+		It("should accept lines padded with trailing whitespace", func() {
+			sat, err := ParseTLEV2(line1+"   \r\n", line2+"\t ", "wgs72")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sat).To(Equal(ParseTLE(line1, line2, "wgs72")))
+		})
+
+		It("should reject lines with embedded tabs", func() {
+			_, err := ParseTLEV2(strings.Replace(line1, "  ", "\t", 1), line2, "wgs72")
+			Expect(errors.Cause(err)).To(Equal(ErrInvalidTLE))
+			Expect(err.Error()).To(ContainSubstring("tab"))
+		})
+
+		It("should reject lines in the wrong order", func() {
+			_, err := ParseTLEV2(line2, line1, "wgs72")
+			Expect(errors.Cause(err)).To(Equal(ErrInvalidTLE))
+		})
+	})
+
+	Describe("ECEFToECI", func() {
+		It("should undo ECIToECEF", func() {
+			v := Vector3{X: 4000.5, Y: -5200.25, Z: 3100.125}
+			for _, gmst := range []float64{0, 1, 3.5, 6} {
+				back := ECEFToECI(ECIToECEF(v, gmst), gmst)
+				Expect(back.sub(v).norm()).To(BeNumerically("<", 1e-9))
+			}
+		})
+	})
+
+	Describe("angle normalization", func() {
+		It("should keep ECIToLLA longitudes in (-pi, pi] and latitudes in [-pi/2, pi/2]", func() {
+			for _, c := range []struct {
+				eci              Vector3
+				gmst             float64
+				wantLat, wantLon float64 // degrees
+			}{
+				{Vector3{X: -7000, Y: 1e-6}, 0, 0, 180},
+				{Vector3{X: -7000, Y: -1e-6}, 0, 0, -180},
+				{Vector3{X: -7000, Y: -1e-6}, 1e-9, 0, 180},
+				{Vector3{X: 7000 * math.Cos(-3), Y: 7000 * math.Sin(-3)}, 1, 0, (2*math.Pi - 4) * RAD2DEG},
+				{Vector3{X: 7000}, 6.2, 0, (2*math.Pi - 6.2) * RAD2DEG},
+				{Vector3{X: 7000 * math.Cos(3), Y: 7000 * math.Sin(3)}, 0.1, 0, (3 - 0.1) * RAD2DEG},
+				{Vector3{X: 1e-9, Z: 7000}, 2, 90, -2 * RAD2DEG},
+				{Vector3{X: 1e-9, Z: -7000}, 5, -90, (2*math.Pi - 5) * RAD2DEG},
+			} {
+				_, _, ll := ECIToLLA(c.eci, c.gmst)
+				Expect(ll.Longitude).To(BeNumerically(">", -math.Pi))
+				Expect(ll.Longitude).To(BeNumerically("<=", math.Pi))
+				Expect(ll.Latitude).To(BeNumerically(">=", -math.Pi/2))
+				Expect(ll.Latitude).To(BeNumerically("<=", math.Pi/2))
+				Expect(ll.Latitude * RAD2DEG).To(BeNumerically("~", c.wantLat, 1e-6))
+				if math.Abs(c.wantLon) != 180 {
+					Expect(ll.Longitude * RAD2DEG).To(BeNumerically("~", c.wantLon, 1e-6))
+				} else {
+					Expect(math.Abs(ll.Longitude * RAD2DEG)).To(BeNumerically("~", 180, 1e-6))
+				}
+			}
+		})
+
+		It("should keep ECIToLookAngles azimuths in [0, 2pi) and elevations in [-pi/2, pi/2]", func() {
+			jday := JDay(2008, 9, 20, 12, 0, 0)
+			for _, c := range []struct {
+				obs    LatLong // degrees
+				az, el float64 // degrees
+				wantAz float64 // degrees
+			}{
+				{LatLong{Latitude: 45, Longitude: 10}, 0, 30, 0},
+				{LatLong{Latitude: 45, Longitude: 10}, 1e-7, 30, 1e-7},
+				{LatLong{Latitude: 45, Longitude: 10}, -1e-7, 30, 360 - 1e-7},
+				{LatLong{Latitude: 45, Longitude: 10}, 359.9999, 0, 359.9999},
+				{LatLong{Latitude: -33.9, Longitude: -70.6}, 90, -10, 90},
+				{LatLong{Latitude: -33.9, Longitude: -70.6}, 180, 45, 180},
+				{LatLong{Latitude: 0, Longitude: 180}, 270, 89.9999, 270},
+				{LatLong{Latitude: 89.9, Longitude: -179.9}, 360, 10, 0},
+				{LatLong{Latitude: -89.9, Longitude: 0}, 720.5, -89.9999, 0.5},
+			} {
+				lat, lon := c.obs.Latitude*DEG2RAD, c.obs.Longitude*DEG2RAD
+				theta := ThetaG_JD(jday) + lon
+				up := Vector3{X: math.Cos(lat) * math.Cos(theta), Y: math.Cos(lat) * math.Sin(theta), Z: math.Sin(lat)}
+				east := Vector3{X: -math.Sin(theta), Y: math.Cos(theta)}
+				north := Vector3{X: -math.Sin(lat) * math.Cos(theta), Y: -math.Sin(lat) * math.Sin(theta), Z: math.Cos(lat)}
+				az, el := c.az*DEG2RAD, c.el*DEG2RAD
+				dir := east.scale(math.Cos(el) * math.Sin(az)).add(north.scale(math.Cos(el) * math.Cos(az))).add(up.scale(math.Sin(el)))
+
+				obsLL := LatLong{Latitude: lat, Longitude: lon}
+				sat := LLAToECI(obsLL, 0, jday).add(dir.scale(1000))
+				look := ECIToLookAngles(sat, obsLL, 0, jday)
+				Expect(look.Az).To(BeNumerically(">=", 0))
+				Expect(look.Az).To(BeNumerically("<", TWOPI))
+				Expect(look.El).To(BeNumerically(">=", -math.Pi/2))
+				Expect(look.El).To(BeNumerically("<=", math.Pi/2))
+				Expect(wrapPi(look.Az - c.wantAz*DEG2RAD)).To(BeNumerically("~", 0, 1e-6))
+				Expect(look.El).To(BeNumerically("~", el, 1e-9))
+			}
+		})
+
+		It("should not return NaN elevations straight overhead", func() {
+			jday := JDay(2008, 9, 20, 12, 0, 0)
+			obs := LatLong{Latitude: 30 * DEG2RAD, Longitude: 60 * DEG2RAD}
+			lat, theta := obs.Latitude, ThetaG_JD(jday)+obs.Longitude
+			up := Vector3{X: math.Cos(lat) * math.Cos(theta), Y: math.Cos(lat) * math.Sin(theta), Z: math.Sin(lat)}
+			look := ECIToLookAngles(LLAToECI(obs, 0, jday).add(up.scale(500)), obs, 0, jday)
+			Expect(look.El).To(BeNumerically("~", math.Pi/2, 1e-6))
+			Expect(look.Az).To(BeNumerically(">=", 0))
+			Expect(look.Az).To(BeNumerically("<", TWOPI))
+		})
+
+		It("should convert longitudes outside (-pi, pi] to degrees on the right side of the antimeridian", func() {
+			for _, c := range []struct{ rad, deg float64 }{
+				{math.Pi + 0.1, -180 + 0.1*RAD2DEG},
+				{-math.Pi - 0.1, 180 - 0.1*RAD2DEG},
+				{3 * math.Pi / 2, -90},
+				{-3 * math.Pi / 2, 90},
+				{math.Pi, 180},
+				{-math.Pi, 180},
+				{5 * math.Pi / 2, 90},
+			} {
+				Expect(LatLongDeg(LatLong{Longitude: c.rad}).Longitude).To(BeNumerically("~", c.deg, 1e-9))
+			}
+		})
+	})
+
+	Describe("LLAToECEF", func() {
+		It("should invert ECIToLLA", func() {
+			for _, lla := range []struct {
+				ll  LatLong
+				alt float64
+			}{
+				{LatLong{Latitude: 0, Longitude: 0}, 0},
+				{LatLong{Latitude: 45 * DEG2RAD, Longitude: 10 * DEG2RAD}, 0.5},
+				{LatLong{Latitude: -33.9 * DEG2RAD, Longitude: -70.6 * DEG2RAD}, 400},
+				{LatLong{Latitude: 89 * DEG2RAD, Longitude: 170 * DEG2RAD}, 35786},
+			} {
+				gmst := 1.234
+				alt, _, ll := ECIToLLA(ECEFToECI(LLAToECEF(lla.ll, lla.alt), gmst), gmst)
+				Expect(alt).To(BeNumerically("~", lla.alt, 1e-6))
+				Expect(ll.Latitude).To(BeNumerically("~", lla.ll.Latitude, 1e-9))
+				Expect(wrapPi(ll.Longitude - lla.ll.Longitude)).To(BeNumerically("~", 0, 1e-9))
+			}
+		})
+
+		It("should put the poles at the semi-minor axis", func() {
+			Expect(LLAToECEF(LatLong{Latitude: math.Pi / 2}, 0).Z).To(BeNumerically("~", 6356.7523142, 1e-6))
+		})
+	})
+
+	Describe("PropagateAt", func() {
+		iss := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+		epoch := time.Date(2008, 9, 20, 12, 25, 40, 104192000, time.UTC)
+
+		It("should propagate to the epoch including its fractional seconds", func() {
+			pos, vel := PropagateAt(&iss, epoch)
+			wantPos, wantVel, err := PropagateMinutes(&iss, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pos.sub(wantPos).norm()).To(BeNumerically("<", 1e-3))
+			Expect(vel.sub(wantVel).norm()).To(BeNumerically("<", 1e-6))
+		})
+
+		It("should carry fractional seconds rather than truncating them", func() {
+			whole, _ := PropagateAt(&iss, epoch.Truncate(time.Second))
+			half, _ := PropagateAt(&iss, epoch.Truncate(time.Second).Add(500*time.Millisecond))
+			Expect(half.sub(whole).norm()).To(BeNumerically("~", 7.7/2, 0.1))
+		})
+
+		It("should interpret the time as UTC whatever its location", func() {
+			utc, _ := PropagateAt(&iss, epoch.Add(time.Hour))
+			local, _ := PropagateAt(&iss, epoch.Add(time.Hour).In(time.FixedZone("UTC+5", 5*3600)))
+			Expect(local).To(Equal(utc))
+		})
+
+		It("should agree with Propagate at whole seconds without modifying the satellite", func() {
+			before := iss
+			pos, _ := PropagateAt(&iss, time.Date(2008, 9, 21, 0, 0, 0, 0, time.UTC))
+			want, _ := Propagate(iss, 2008, 9, 21, 0, 0, 0)
+			Expect(pos).To(Equal(want))
+			Expect(iss).To(Equal(before))
+		})
+	})
+
+	Describe("concurrent propagation", func() {
+		It("should give every goroutine the same results from one shared satellite", func() {
+			for _, sat := range []Satellite{
+				TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72"),
+				TLEToSat("1 24208U 96044A   06177.04061740 -.00000094  00000-0  10000-3 0  1600", "2 24208   3.8536  80.0121 0026640 311.0977  48.3000  1.00778054 36119", "wgs72"),
+			} {
+				shared := sat.Clone()
+				want := make([]Vector3, 50)
+				for i := range want {
+					want[i], _, _ = PropagateMinutes(shared, float64(i*30))
+				}
+
+				var wg sync.WaitGroup
+				got := make([][]Vector3, 8)
+				for g := range got {
+					wg.Add(1)
+					go func(g int) {
+						defer wg.Done()
+						got[g] = make([]Vector3, len(want))
+						for i := range want {
+							got[g][i], _, _ = PropagateMinutes(shared, float64(i*30))
+						}
+					}(g)
+				}
+				wg.Wait()
+
+				for g := range got {
+					Expect(got[g]).To(Equal(want))
+				}
+				Expect(*shared).To(Equal(sat))
+			}
+		})
+
+		It("should clone a satellite into an independent copy", func() {
+			iss := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+			clone := iss.Clone()
+			Expect(*clone).To(Equal(iss))
+			clone.Name = "ISS"
+			Expect(iss.Name).To(BeEmpty())
+		})
+	})
+
+	Describe("expandEpochYear", func() {
+		It("should window two digit years into 1957-2056", func() {
+			Expect(expandEpochYear(57)).To(Equal(1957))
+			Expect(expandEpochYear(99)).To(Equal(1999))
+			Expect(expandEpochYear(0)).To(Equal(2000))
+			Expect(expandEpochYear(8)).To(Equal(2008))
+			Expect(expandEpochYear(56)).To(Equal(2056))
+		})
+
+		It("should not make up a year for values outside 0-99", func() {
+			Expect(expandEpochYear(-1)).To(BeZero())
+			Expect(expandEpochYear(100)).To(BeZero())
+			Expect(expandEpochYear(2008)).To(BeZero())
+		})
+	})
+
+	Describe("JSON", func() {
+		line1 := "1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927"
+		line2 := "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537"
+
+		It("should round trip a satellite so that it propagates identically", func() {
+			for _, sat := range []Satellite{
+				TLEToSat(line1, line2, GravityWGS72),
+				TLEToSatWithOpsMode(line1, line2, GravityWGS84, OpsModeAFSPC),
+				TLEToSat("1 24208U 96044A   06177.04061740 -.00000094  00000-0  10000-3 0  1600", "2 24208   3.8536  80.0121 0026640 311.0977  48.3000  1.00778054 36119", GravityWGS72Old),
+			} {
+				sat.Name = "SAT"
+				data, err := json.Marshal(sat)
+				Expect(err).NotTo(HaveOccurred())
+
+				var loaded Satellite
+				Expect(json.Unmarshal(data, &loaded)).To(Succeed())
+				Expect(loaded).To(Equal(sat))
+				for _, minutes := range []float64{0, 90, 1440} {
+					pos, vel, _ := PropagateMinutes(&loaded, minutes)
+					wantPos, wantVel, _ := PropagateMinutes(&sat, minutes)
+					Expect(pos).To(Equal(wantPos))
+					Expect(vel).To(Equal(wantVel))
+				}
+			}
+		})
+
+		It("should write the lines, gravity model and operation mode", func() {
+			data, err := json.Marshal(TLEToSat(line1, line2, GravityWGS84))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(MatchJSON(`{"TLE_LINE1":"` + line1 + `","TLE_LINE2":"` + line2 + `","GRAVITY":"wgs84","OPS_MODE":"i"}`))
+		})
+
+		It("should format the lines of satellites built from elements", func() {
+			sat, err := ElementsToSat(Elements{
+				SatNum:       99999,
+				Epoch:        time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC),
+				Inclination:  97.5,
+				Eccentricity: 0.001,
+				MeanMotion:   15.2,
+			}, GravityWGS72)
+			Expect(err).NotTo(HaveOccurred())
+			data, err := json.Marshal(&sat)
+			Expect(err).NotTo(HaveOccurred())
+
+			var loaded Satellite
+			Expect(json.Unmarshal(data, &loaded)).To(Succeed())
+			Expect(loaded.SatNum()).To(Equal(int64(99999)))
+			pos, _, _ := PropagateMinutes(&loaded, 60)
+			wantPos, _, _ := PropagateMinutes(&sat, 60)
+			Expect(pos.sub(wantPos).norm()).To(BeNumerically("<", 1))
+		})
+
+		It("should read Space-Track records with WGS72 in the improved mode", func() {
+			data := `[{"OBJECT_NAME":"ISS (ZARYA)","NORAD_CAT_ID":"25544","TLE_LINE0":"0 ISS (ZARYA)","TLE_LINE1":"` + line1 + `","TLE_LINE2":"` + line2 + `"}]`
+			var sats []Satellite
+			Expect(json.Unmarshal([]byte(data), &sats)).To(Succeed())
+			want := TLEToSat(line1, line2, GravityWGS72)
+			want.Name = "ISS (ZARYA)"
+			Expect(sats).To(Equal([]Satellite{want}))
+		})
+
+		It("should reject malformed lines", func() {
+			var sat Satellite
+			err := json.Unmarshal([]byte(`{"TLE_LINE1":"1 25544U","TLE_LINE2":"`+line2+`"}`), &sat)
+			Expect(errors.Cause(err)).To(Equal(ErrInvalidTLE))
+			err = json.Unmarshal([]byte(`{"TLE_LINE1":"`+line1+`","TLE_LINE2":"`+line2+`","GRAVITY":"wgs99"}`), &sat)
+			Expect(errors.Cause(err)).To(Equal(ErrUnknownGravity))
+			err = json.Unmarshal([]byte(`{"TLE_LINE1":"`+line1+`","TLE_LINE2":"`+line2+`","OPS_MODE":"x"}`), &sat)
+			Expect(errors.Cause(err)).To(Equal(ErrUnknownOpsMode))
+		})
+	})
+
+	Describe("GravityConstants", func() {
+		It("should return the constants the propagator uses", func() {
+			for _, g := range []Gravity{GravityWGS72Old, GravityWGS72, GravityWGS84} {
+				params, err := GravityConstants(g)
+				Expect(err).NotTo(HaveOccurred())
+				grav, _ := getGravConstV2(g)
+				Expect(params.RadiusEarthKm).To(Equal(grav.radiusearthkm))
+				Expect(params.Mu).To(Equal(grav.mu))
+				Expect(params.J2).To(Equal(grav.j2))
+				Expect(params.J3).To(Equal(grav.j3))
+				Expect(params.J4).To(Equal(grav.j4))
+				Expect(params.XKE).To(Equal(grav.xke))
+			}
+			wgs84, _ := GravityConstants(GravityWGS84)
+			Expect(wgs84.RadiusEarthKm).To(Equal(6378.137))
+			Expect(wgs84.Mu).To(Equal(398600.5))
+		})
+
+		It("should reject unknown models", func() {
+			for _, g := range []Gravity{"", "wgs99", "WGS72"} {
+				_, err := GravityConstants(g)
+				Expect(errors.Cause(err)).To(Equal(ErrUnknownGravity))
+			}
+		})
+
+		It("should return a copy", func() {
+			params, _ := GravityConstants(GravityWGS72)
+			params.Mu = 1
+			again, _ := GravityConstants(GravityWGS72)
+			Expect(again.Mu).To(Equal(398600.8))
+		})
+	})
+
+	Describe("Err", func() {
+		It("should map each SGP4 error code to its own sentinel", func() {
+			Expect((&Satellite{}).Err()).To(BeNil())
+			seen := map[error]bool{}
+			for code := int64(1); code <= 6; code++ {
+				err := (&Satellite{Error: code}).Err()
+				Expect(err).To(HaveOccurred())
+				Expect(seen[err]).To(BeFalse())
+				seen[err] = true
+			}
+			Expect((&Satellite{Error: 6}).Err()).To(Equal(ErrDecayed))
+			Expect((&Satellite{Error: 9, ErrorStr: "odd"}).Err().Error()).To(ContainSubstring("odd"))
+		})
+
+		It("should be reported by PropagateAtChecked once the satellite decays", func() {
+			decayed := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0  10000-1 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+			epoch := time.Date(2008, 9, 20, 12, 25, 40, 104192000, time.UTC)
+
+			_, _, err := PropagateAtChecked(&decayed, epoch.Add(24*time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			_, _, err = PropagateAtChecked(&decayed, epoch.Add(20*24*time.Hour))
+			Expect(err).To(Equal(ErrDecayed))
+			Expect(decayed.Err()).To(BeNil())
+
+			pos, vel, err := PropagateAtChecked(&decayed, epoch.Add(time.Hour))
+			wantPos, wantVel := PropagateAt(&decayed, epoch.Add(time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pos).To(Equal(wantPos))
+			Expect(vel).To(Equal(wantVel))
+		})
+	})
+
+	Describe("PropagateRange", func() {
+		iss := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+		start := time.Date(2008, 9, 20, 13, 0, 0, 0, time.UTC)
+
+		It("should propagate every step including the end", func() {
+			positions, velocities, times, err := PropagateRange(&iss, start, start.Add(10*time.Minute+30*time.Second), time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(times).To(HaveLen(12))
+			Expect(positions).To(HaveLen(12))
+			Expect(velocities).To(HaveLen(12))
+			Expect(times[11]).To(Equal(start.Add(10*time.Minute + 30*time.Second)))
+
+			for i, t := range times {
+				pos, vel := PropagateAt(&iss, t)
+				Expect(positions[i]).To(Equal(pos))
+				Expect(velocities[i]).To(Equal(vel))
+			}
+		})
+
+		It("should reject a non-positive step or reversed range", func() {
+			_, _, _, err := PropagateRange(&iss, start, start.Add(time.Hour), 0)
+			Expect(err).To(Equal(ErrInvalidStep))
+			_, _, _, err = PropagateRange(&iss, start, start.Add(-time.Hour), time.Minute)
+			Expect(err).To(Equal(ErrInvalidTimeRange))
+		})
+	})
+
+	Describe("PropagateSeconds", func() {
+		iss := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+
+		It("should match PropagateMinutes and the core propagator", func() {
+			posS, velS, err := PropagateSeconds(&iss, 5400)
+			Expect(err).NotTo(HaveOccurred())
+			posM, velM, err := PropagateMinutes(&iss, 90)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(posS).To(Equal(posM))
+			Expect(velS).To(Equal(velM))
+
+			s := iss
+			pos, vel := sgp4(&s, 90)
+			Expect(posM).To(Equal(pos))
+			Expect(velM).To(Equal(vel))
+		})
+
+		It("should report propagation errors", func() {
+			// bstar raised a thousandfold decays the orbit within two weeks
+			decayed := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0  10000-1 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+			_, _, err := PropagateMinutes(&decayed, 20*1440)
+			Expect(err).To(HaveOccurred())
+			Expect(errors.Cause(err)).NotTo(Equal(ErrHorizonExceeded))
+		})
+
+		It("should refuse times beyond MaxPropagationSpan unless disabled", func() {
+			_, _, err := PropagateMinutes(&iss, -31*1440)
+			Expect(errors.Cause(err)).To(Equal(ErrHorizonExceeded))
+			_, _, err = PropagateAtChecked(&iss, time.Date(2009, 1, 1, 0, 0, 0, 0, time.UTC))
+			Expect(errors.Cause(err)).To(Equal(ErrHorizonExceeded))
+
+			defer func(span time.Duration) { MaxPropagationSpan = span }(MaxPropagationSpan)
+			MaxPropagationSpan = 0
+			_, _, err = PropagateMinutes(&iss, -31*1440)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should leave the analysis helpers unlimited", func() {
+			_, _, err := propagateTime(iss, time.Date(2009, 1, 1, 0, 0, 0, 0, time.UTC))
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Describe("UT1MinusUTC", func() {
+		It("should advance sidereal time by the Earth's rotation over the offset", func() {
+			jday := JDay(2008, 9, 20, 12, 0, 0)
+			before, beforeGST := ThetaG_JD(jday), gstime(jday)
+
+			defer func() { UT1MinusUTC = 0 }()
+			UT1MinusUTC = 500 * time.Millisecond
+			Expect(ThetaG_JD(jday) - before).To(BeNumerically("~", 0.5*earthRotationRate, 1e-8))
+			Expect(gstime(jday) - beforeGST).To(BeNumerically("~", 0.5*earthRotationRate, 1e-8))
+		})
+	})
+
+	Describe("LASTFromTime", func() {
+		// Meeus, Astronomical Algorithms, example 12.b
+		t := time.Date(1987, 4, 10, 19, 21, 0, 0, time.UTC)
+		secondsOfTime := TWOPI / 86400
+
+		It("should add the equation of the equinoxes to mean sidereal time", func() {
+			Expect(gstime(jdayFromTime(t))).To(BeNumerically("~", (8*3600+34*60+57.0896)*secondsOfTime, 0.001*secondsOfTime))
+			Expect(LASTFromTime(t, 0)).To(BeNumerically("~", (8*3600+34*60+56.8579)*secondsOfTime, 0.02*secondsOfTime))
+		})
+
+		It("should add the observer longitude and stay within [0, 2pi)", func() {
+			Expect(LASTFromTime(t, 90)).To(BeNumerically("~", LASTFromTime(t, 0)+math.Pi/2, 1e-12))
+			for _, lon := range []float64{-180, -179, 179, 180, 540} {
+				last := LASTFromTime(t, lon)
+				Expect(last).To(BeNumerically(">=", 0))
+				Expect(last).To(BeNumerically("<", TWOPI))
+			}
+		})
+	})
+
+	Describe("GMSTFromTime and GASTFromTime", func() {
+		// Meeus, Astronomical Algorithms, example 12.b
+		t := time.Date(1987, 4, 10, 19, 21, 0, 0, time.UTC)
+		secondsOfTime := TWOPI / 86400
+
+		It("should match the published mean and apparent sidereal times", func() {
+			Expect(GMSTFromTime(t)).To(BeNumerically("~", (8*3600+34*60+57.0896)*secondsOfTime, 0.001*secondsOfTime))
+			Expect(GASTFromTime(t)).To(BeNumerically("~", (8*3600+34*60+56.8579)*secondsOfTime, 0.02*secondsOfTime))
+		})
+
+		It("should agree with the sidereal time used internally", func() {
+			Expect(GMSTFromTime(t)).To(Equal(GSTimeFromDate(1987, 4, 10, 19, 21, 0)))
+			Expect(GASTFromTime(t)).To(Equal(LASTFromTime(t, 0)))
+			Expect(GMSTFromTime(t.In(time.FixedZone("UTC+2", 2*3600)))).To(Equal(GMSTFromTime(t)))
+		})
+
+		It("should keep fractional seconds", func() {
+			Expect(GMSTFromTime(t.Add(500*time.Millisecond)) - GMSTFromTime(t)).To(BeNumerically("~", 0.5*earthRotationRate, 1e-8))
+		})
+	})
+
+	Describe("CompareNddotEffect", func() {
+		// An element set with a large nddot, from the SGP4 verification cases
+		sat := TLEToSat("1 23599U 95029B   06171.76535463  .00085586  12891-6  12956-2 0  2905", "2 23599   6.9327   0.2849 5782022 274.4436  25.2425  4.47796565123555", "wgs72")
+
+		It("should show that SGP4 ignores nddot", func() {
+			Expect(sat.nddot).NotTo(BeZero())
+			diff, err := CompareNddotEffect(sat, time.Date(2006, 6, 27, 0, 0, 0, 0, time.UTC))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff).To(BeZero())
+		})
+
+		It("should work for satellites built from elements, which have no TLE lines", func() {
+			fromElements, err := ElementsToSat(Elements{
+				SatNum:         23599,
+				Epoch:          sat.Epoch(),
+				Inclination:    6.9327,
+				RAAN:           0.2849,
+				Eccentricity:   0.5782022,
+				ArgPerigee:     274.4436,
+				MeanAnomaly:    25.2425,
+				MeanMotion:     4.47796565,
+				Bstar:          0.0012956,
+				MeanMotionDot:  0.00085586,
+				MeanMotionDDot: 1.2891e-7,
+			}, GravityWGS72)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fromElements.Line1).To(BeEmpty())
+			Expect(fromElements.nddot).NotTo(BeZero())
+
+			diff, err := CompareNddotEffect(fromElements, sat.Epoch().Add(5*24*time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff).To(BeZero())
+		})
+	})
+
+	Describe("PropagateECEF", func() {
+		iss := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+		t := time.Date(2008, 9, 20, 13, 0, 0, 0, time.UTC)
+
+		It("should rotate the ECI position onto the subpoint longitude", func() {
+			pos, _, err := PropagateECEF(iss, t)
+			Expect(err).NotTo(HaveOccurred())
+			eci, _, err := propagateTime(iss, t)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pos.norm()).To(BeNumerically("~", eci.norm(), 1e-9))
+
+			ll, _, err := subpoint(iss, t)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(math.Atan2(pos.Y, pos.X)).To(BeNumerically("~", ll.Longitude, 1e-9))
+		})
+
+		It("should remove the Earth's rotation from the velocity", func() {
+			_, vel, err := PropagateECEF(iss, t)
+			Expect(err).NotTo(HaveOccurred())
+			_, eciVel, err := propagateTime(iss, t)
+			Expect(err).NotTo(HaveOccurred())
+			// A prograde orbit moves slower relative to the ground, by up to ωr at the equator
+			Expect(vel.norm()).To(BeNumerically("<", eciVel.norm()))
+			Expect(eciVel.norm() - vel.norm()).To(BeNumerically("<", 0.5))
+		})
+	})
+
 	Describe("Propagate", func() {
 		testCases := [8]PropagationTestCase{
 			// PropagationTestCase{
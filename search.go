@@ -0,0 +1,145 @@
+package satellite
+
+import (
+	"time"
+)
+
+// Precision to which event times are refined
+const searchTolerance = time.Millisecond
+
+// Describes a change in the value of a condition between two samples
+type transition struct {
+	t      time.Time
+	rising bool // true when the condition changed from false to true
+}
+
+// Narrows [a, b], over which cond changes away from its value condA at a, and returns the
+// first time within searchTolerance at which cond no longer holds condA.
+func refineTransition(a, b time.Time, condA bool, cond func(time.Time) (bool, error)) (time.Time, error) {
+	for b.Sub(a) > searchTolerance {
+		mid := a.Add(b.Sub(a) / 2)
+		c, err := cond(mid)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if c == condA {
+			a = mid
+		} else {
+			b = mid
+		}
+	}
+	return b, nil
+}
+
+// Samples cond from start to end inclusive every step and returns the value of cond at start along
+// with every change of value, refined by bisection. Changes that revert within a single step are missed.
+func findTransitions(start, end time.Time, step time.Duration, cond func(time.Time) (bool, error)) (initial bool, transitions []transition, err error) {
+	times, err := sampleTimes(start, end, step)
+	if err != nil {
+		return false, nil, err
+	}
+
+	prev, err := cond(times[0])
+	if err != nil {
+		return false, nil, err
+	}
+	initial = prev
+
+	for i := 1; i < len(times); i++ {
+		c, err := cond(times[i])
+		if err != nil {
+			return false, nil, err
+		}
+		if c != prev {
+			t, err := refineTransition(times[i-1], times[i], prev, cond)
+			if err != nil {
+				return false, nil, err
+			}
+			transitions = append(transitions, transition{t: t, rising: c})
+		}
+		prev = c
+	}
+
+	return initial, transitions, nil
+}
+
+// A span of time during which a condition holds
+type interval struct {
+	start, end time.Time
+}
+
+// Converts the result of findTransitions over [start, end] into the intervals during which the condition
+// held. An interval already under way at start begins at start, and one still under way at end finishes at end.
+func transitionIntervals(start, end time.Time, initial bool, transitions []transition) []interval {
+	var intervals []interval
+	from, holds := start, initial
+	for _, tr := range transitions {
+		if tr.rising {
+			from = tr.t
+		} else {
+			intervals = append(intervals, interval{start: from, end: tr.t})
+		}
+		holds = tr.rising
+	}
+	if holds {
+		intervals = append(intervals, interval{start: from, end: end})
+	}
+	return intervals
+}
+
+// Refines the minimum of f bracketed by a < b < c, where fb = f(b) is no greater than f at either
+// end, using successive parabolic interpolation. Returns the time and value of the minimum found.
+func refineMinimum(a, b, c time.Time, fb float64, f func(time.Time) (float64, error)) (time.Time, float64, error) {
+	fa, err := f(a)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	fc, err := f(c)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+
+	for i := 0; i < 100 && c.Sub(a) > searchTolerance; i++ {
+		// Vertex of the parabola through the three points, in seconds relative to b
+		xa := a.Sub(b).Seconds()
+		xc := c.Sub(b).Seconds()
+		num := xa*xa*(fb-fc) - xc*xc*(fb-fa)
+		den := xa*(fb-fc) - xc*(fb-fa)
+
+		x := 0.0
+		if den != 0 {
+			x = 0.5 * num / den
+		}
+		// Fall back to halving the larger side when the parabola is degenerate
+		if den == 0 || x <= xa || x >= xc || x == 0 {
+			if -xa > xc {
+				x = xa / 2
+			} else {
+				x = xc / 2
+			}
+		}
+
+		u := b.Add(time.Duration(x * float64(time.Second)))
+		fu, err := f(u)
+		if err != nil {
+			return time.Time{}, 0, err
+		}
+
+		if fu < fb {
+			if u.Before(b) {
+				c, fc = b, fb
+			} else {
+				a, fa = b, fb
+			}
+			b, fb = u, fu
+		} else {
+			if u.Before(b) {
+				a, fa = u, fu
+			} else {
+				c, fc = u, fu
+			}
+		}
+	}
+
+	return b, fb, nil
+}
@@ -2,6 +2,9 @@ package satellite
 
 import (
 	"math"
+	"time"
+
+	"github.com/pkg/errors"
 )
 
 // this procedure initializes variables for sgp4.
@@ -12,7 +15,7 @@ func sgp4init(opsmode *string, epoch float64, satrec *Satellite) (position, velo
 	var cosim, sinim, em, emsq, argpm, nodem, inclm, mm, nm, s1, s2, s3, s4, s5, ss1, ss2, ss3, ss4, ss5, sz1, sz3, sz11, sz13, sz21, sz23, sz31, sz33, tc, z1, z3, z11, z13, z21, z23, z31, z33, xpidot float64
 
 	satrec.method = "n"
-	satrec.operationmode = "i"
+	satrec.operationmode = *opsmode
 
 	radiusearthkm := satrec.whichconst.radiusearthkm
 	j2 := satrec.whichconst.j2
@@ -180,7 +183,7 @@ func sgp4init(opsmode *string, epoch float64, satrec *Satellite) (position, velo
 			satrec.zmol = dscomResults.zmol
 			satrec.zmos = dscomResults.zmos
 
-			dpperResults := dpper(satrec, inclm, satrec.init, satrec.ecco, satrec.inclo, satrec.nodeo, satrec.argpo, satrec.mo, satrec.operationmode)
+			dpperResults := dpper(satrec, satrec.t, inclm, satrec.init, satrec.ecco, satrec.inclo, satrec.nodeo, satrec.argpo, satrec.mo, satrec.operationmode)
 
 			satrec.ecco = dpperResults.ep
 			satrec.inclo = dpperResults.inclp
@@ -291,6 +294,13 @@ func initl(satn int64, grav GravConst, ecco, epoch, inclo, noIn float64, methodI
 	return
 }
 
+// State holds a satellite's ECI position (km) and velocity (km/s) at a point in time
+type State struct {
+	Time     time.Time
+	Position Vector3
+	Velocity Vector3
+}
+
 // Calculates position and velocity vectors for given time
 func Propagate(sat Satellite, year int, month int, day, hours, minutes, seconds int) (position, velocity Vector3) {
 	j := JDay(year, month, day, hours, minutes, seconds)
@@ -298,6 +308,113 @@ func Propagate(sat Satellite, year int, month int, day, hours, minutes, seconds
 	return sgp4(&sat, m)
 }
 
+// PropagateAt calculates position (km) and velocity (km/s) vectors at time t, which is interpreted as UTC
+// whatever its location, keeping its fractional seconds. sat isn't modified. Unlike PropagateMinutes it
+// ignores MaxPropagationSpan and doesn't report SGP4 errors; PropagateAtChecked honours the one and
+// reports the other.
+func PropagateAt(sat *Satellite, t time.Time) (position, velocity Vector3) {
+	s := *sat
+	return sgp4(&s, minutesSinceEpoch(&s, t.UTC()))
+}
+
+// PropagateAtChecked is PropagateAt reporting failures: the SGP4 error sentinels returned by Err, such as
+// ErrDecayed, when the propagation diverges and ErrHorizonExceeded beyond MaxPropagationSpan.
+func PropagateAtChecked(sat *Satellite, t time.Time) (position, velocity Vector3, err error) {
+	return PropagateMinutes(sat, minutesSinceEpoch(sat, t))
+}
+
+// MaxPropagationSpan is the furthest from its epoch, in either direction, that PropagateMinutes,
+// PropagateSeconds, PropagateAtChecked and Propagator.At will propagate a satellite before failing with
+// ErrHorizonExceeded. Element sets are rarely useful beyond a few weeks, and far beyond that SGP4 produces
+// meaningless or NaN results while still costing CPU. The analysis functions, such as coverage over a
+// repeat cycle or a decay search, run over spans of their caller's choosing and don't apply it. Zero
+// disables the check. Set it before any goroutine propagates.
+var MaxPropagationSpan = 30 * 24 * time.Hour
+
+var ErrHorizonExceeded = errors.New("requested time is too far from the element set epoch")
+
+// PropagateMinutes calculates position (km) and velocity (km/s) vectors tsinceMin minutes after the
+// satellite's epoch, the native time argument of SGP4. sat isn't modified. Times further than
+// MaxPropagationSpan from epoch give ErrHorizonExceeded, and failed propagations the error from Err.
+func PropagateMinutes(sat *Satellite, tsinceMin float64) (position, velocity Vector3, err error) {
+	if err := checkPropagationSpan(tsinceMin); err != nil {
+		return Vector3{}, Vector3{}, err
+	}
+	return propagateMinutes(sat, tsinceMin)
+}
+
+// Returns an error wrapping ErrHorizonExceeded if tsinceMin minutes from epoch is beyond MaxPropagationSpan
+func checkPropagationSpan(tsinceMin float64) error {
+	if MaxPropagationSpan > 0 && math.Abs(tsinceMin) > MaxPropagationSpan.Minutes() {
+		return errors.Wrapf(ErrHorizonExceeded, "%.1f days from epoch", tsinceMin/1440)
+	}
+	return nil
+}
+
+// Calculates position and velocity vectors tsinceMin minutes after epoch whatever MaxPropagationSpan is,
+// reporting SGP4 errors. sat isn't modified.
+func propagateMinutes(sat *Satellite, tsinceMin float64) (position, velocity Vector3, err error) {
+	s := *sat
+	position, velocity = sgp4(&s, tsinceMin)
+	return position, velocity, s.Err()
+}
+
+// PropagateSeconds is PropagateMinutes with the time since epoch given in seconds. It is exactly
+// PropagateMinutes(sat, tsinceSec/60).
+func PropagateSeconds(sat *Satellite, tsinceSec float64) (position, velocity Vector3, err error) {
+	return PropagateMinutes(sat, tsinceSec/60.0)
+}
+
+// Returns the minutes elapsed from the satellite epoch to the given time
+func minutesSinceEpoch(sat *Satellite, t time.Time) float64 {
+	return (jdayFromTime(t) - sat.jdsatepoch) * 1440.0
+}
+
+// Calculates position and velocity vectors for the given time, reporting any propagation error but
+// ignoring MaxPropagationSpan. sat is passed by value so the caller's Satellite is never modified.
+func propagateTime(sat Satellite, t time.Time) (position, velocity Vector3, err error) {
+	return propagateMinutes(&sat, minutesSinceEpoch(&sat, t))
+}
+
+// Calculates position and velocity vectors at each step from start to end inclusive
+func propagateRange(sat Satellite, start, end time.Time, step time.Duration) (positions, velocities []Vector3, times []time.Time, err error) {
+	times, err = sampleTimes(start, end, step)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	positions = make([]Vector3, len(times))
+	velocities = make([]Vector3, len(times))
+	for i, t := range times {
+		positions[i], velocities[i], err = propagateTime(sat, t)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	return
+}
+
+// PropagateRange calculates position (km) and velocity (km/s) vectors every step from start to end,
+// always including end, and returns them with their times in parallel slices. sat isn't modified. It
+// returns ErrInvalidStep if step isn't positive, ErrInvalidTimeRange if end precedes start and the
+// first propagation error otherwise.
+func PropagateRange(sat *Satellite, start, end time.Time, step time.Duration) ([]Vector3, []Vector3, []time.Time, error) {
+	return propagateRange(*sat, start, end, step)
+}
+
+// PropagateECEF calculates the satellite's position (km) and velocity (km/s) at time t in Earth Centered
+// Earth Fixed coordinates. The velocity is relative to the rotating Earth, so a geostationary satellite
+// has a velocity near zero.
+func PropagateECEF(sat Satellite, t time.Time) (posECEF, velECEF Vector3, err error) {
+	pos, vel, err := propagateTime(sat, t)
+	if err != nil {
+		return Vector3{}, Vector3{}, err
+	}
+	posECEF, velECEF = eciToECEFState(pos, vel, gstime(jdayFromTime(t)))
+	return posECEF, velECEF, nil
+}
+
 // this procedure is the sgp4 prediction model from space command. this is an updated and combined version of sgp4 and sdp4, which were originally published separately in spacetrack report #3. this version follows the methodology from the aiaa paper (2006) describing the history and development of the code.
 // satrec - initialized Satellite struct from sgp4init
 // tsince - time since epoch in minutes
@@ -315,33 +432,37 @@ func sgp4(satrec *Satellite, tsince float64) (position, velocity Vector3) {
 
 	vkmpersec := radiusearthkm * xke / 60.0
 
-	satrec.t = tsince
+	// Scratch values are kept local so that propagating only writes the Error fields of satrec
+	t := tsince
+	aycof, xlcof := satrec.aycof, satrec.xlcof
+	con41, x1mth2, x7thm1 := satrec.con41, satrec.x1mth2, satrec.x7thm1
+
 	satrec.Error = 0
 	// TODO: satrec.Error_message    = nil
 
-	xmdf = satrec.mo + satrec.mdot*satrec.t
-	var argpdf = satrec.argpo + satrec.argpdot*satrec.t
-	nodedf = satrec.nodeo + satrec.nodedot*satrec.t
+	xmdf = satrec.mo + satrec.mdot*t
+	var argpdf = satrec.argpo + satrec.argpdot*t
+	nodedf = satrec.nodeo + satrec.nodedot*t
 	argpm = argpdf
 	mm = xmdf
-	t2 = satrec.t * satrec.t
+	t2 = t * t
 	nodem = nodedf + satrec.nodecf*t2
-	tempa = 1.0 - satrec.cc1*satrec.t
-	tempe = satrec.bstar * satrec.cc4 * satrec.t
+	tempa = 1.0 - satrec.cc1*t
+	tempe = satrec.bstar * satrec.cc4 * t
 	templ = satrec.t2cof * t2
 
 	if satrec.isimp != 1 {
-		delomg = satrec.omgcof * satrec.t
+		delomg = satrec.omgcof * t
 		delmtemp := 1.0 + satrec.eta*math.Cos(xmdf)
 		delm = satrec.xmcof * (delmtemp*delmtemp*delmtemp - satrec.delmo)
 		temp = delomg + delm
 		mm = xmdf + temp
 		argpm = argpdf - temp
-		t3 = t2 * satrec.t
-		t4 = t3 * satrec.t
+		t3 = t2 * t
+		t4 = t3 * t
 		tempa = tempa - satrec.d2*t2 - satrec.d3*t3 - satrec.d4*t4
 		tempe = tempe + satrec.bstar*satrec.cc5*(math.Sin(mm)-satrec.sinmao)
-		templ = templ + satrec.t3cof*t3 + t4*(satrec.t4cof+satrec.t*satrec.t5cof)
+		templ = templ + satrec.t3cof*t3 + t4*(satrec.t4cof+t*satrec.t5cof)
 	}
 
 	nm = satrec.no
@@ -349,9 +470,9 @@ func sgp4(satrec *Satellite, tsince float64) (position, velocity Vector3) {
 	inclm = satrec.inclo
 
 	if satrec.method == "d" {
-		tc = satrec.t
+		tc = t
 
-		dspaceResult := dspace(satrec.irez, satrec.d2201, satrec.d2211, satrec.d3210, satrec.d3222, satrec.d4410, satrec.d4422, satrec.d5220, satrec.d5232, satrec.d5421, satrec.d5433, satrec.dedt, satrec.del1, satrec.del2, satrec.del3, satrec.didt, satrec.dmdt, satrec.dnodt, satrec.domdt, satrec.argpo, satrec.argpdot, satrec.t, tc, satrec.gsto, satrec.xfact, satrec.xlamo, satrec.no, satrec.atime, em, argpm, inclm, satrec.xli, mm, satrec.xni, nodem, nm)
+		dspaceResult := dspace(satrec.irez, satrec.d2201, satrec.d2211, satrec.d3210, satrec.d3222, satrec.d4410, satrec.d4422, satrec.d5220, satrec.d5232, satrec.d5421, satrec.d5433, satrec.dedt, satrec.del1, satrec.del2, satrec.del3, satrec.didt, satrec.dmdt, satrec.dnodt, satrec.domdt, satrec.argpo, satrec.argpdot, t, tc, satrec.gsto, satrec.xfact, satrec.xlamo, satrec.no, satrec.atime, em, argpm, inclm, satrec.xli, mm, satrec.xni, nodem, nm)
 
 		em = dspaceResult.em
 		argpm = dspaceResult.argpm
@@ -400,7 +521,7 @@ func sgp4(satrec *Satellite, tsince float64) (position, velocity Vector3) {
 	cosip = cosim
 
 	if satrec.method == "d" {
-		dpperResults := dpper(satrec, satrec.inclo, "n", ep, xincp, nodep, argpp, mp, satrec.operationmode)
+		dpperResults := dpper(satrec, t, satrec.inclo, "n", ep, xincp, nodep, argpp, mp, satrec.operationmode)
 
 		ep = dpperResults.ep
 		xincp = dpperResults.inclp
@@ -423,18 +544,18 @@ func sgp4(satrec *Satellite, tsince float64) (position, velocity Vector3) {
 	if satrec.method == "d" {
 		sinip = math.Sin(xincp)
 		cosip = math.Cos(xincp)
-		satrec.aycof = -0.5 * j3oj2 * sinip
+		aycof = -0.5 * j3oj2 * sinip
 		if math.Abs(cosip+1.0) > 1.5e-12 {
-			satrec.xlcof = -0.25 * j3oj2 * sinip * (3.0 + 5.0*cosip) / (1.0 + cosip)
+			xlcof = -0.25 * j3oj2 * sinip * (3.0 + 5.0*cosip) / (1.0 + cosip)
 		} else {
-			satrec.xlcof = -0.25 * j3oj2 * sinip * (3.0 + 5.0*cosip) / temp4
+			xlcof = -0.25 * j3oj2 * sinip * (3.0 + 5.0*cosip) / temp4
 		}
 	}
 
 	axnl = ep * math.Cos(argpp)
 	temp = 1.0 / (am * (1.0 - ep*ep))
-	aynl = ep*math.Sin(argpp) + temp*satrec.aycof
-	xl = mp + argpp + nodep + temp*satrec.xlcof*axnl
+	aynl = ep*math.Sin(argpp) + temp*aycof
+	xl = mp + argpp + nodep + temp*xlcof*axnl
 
 	u = math.Mod((xl - nodep), TWOPI)
 	eo1 = u
@@ -482,17 +603,17 @@ func sgp4(satrec *Satellite, tsince float64) (position, velocity Vector3) {
 
 		if satrec.method == "d" {
 			cosisq = cosip * cosip
-			satrec.con41 = 3.0*cosisq - 1.0
-			satrec.x1mth2 = 1.0 - cosisq
-			satrec.x7thm1 = 7.0*cosisq - 1.0
+			con41 = 3.0*cosisq - 1.0
+			x1mth2 = 1.0 - cosisq
+			x7thm1 = 7.0*cosisq - 1.0
 		}
 
-		mrt = rl*(1.0-1.5*temp2*betal*satrec.con41) + 0.5*temp1*satrec.x1mth2*cos2u
-		su = su - 0.25*temp2*satrec.x7thm1*sin2u
+		mrt = rl*(1.0-1.5*temp2*betal*con41) + 0.5*temp1*x1mth2*cos2u
+		su = su - 0.25*temp2*x7thm1*sin2u
 		xnode = nodep + 1.5*temp2*cosip*sin2u
 		xinc = xincp + 1.5*temp2*cosip*sinip*cos2u
-		mvt := rdotl - nm*temp1*satrec.x1mth2*sin2u/xke
-		rvdot = rvdotl + nm*temp1*(satrec.x1mth2*cos2u+1.5*satrec.con41)/xke
+		mvt := rdotl - nm*temp1*x1mth2*sin2u/xke
+		rvdot = rvdotl + nm*temp1*(x1mth2*cos2u+1.5*con41)/xke
 
 		sinsu = math.Sin(su)
 		cossu = math.Cos(su)
@@ -527,3 +648,27 @@ func sgp4(satrec *Satellite, tsince float64) (position, velocity Vector3) {
 
 	return
 }
+
+// CompareNddotEffect returns the distance in km at time t between the satellite propagated from its
+// element set as given and from the same element set with the second derivative of mean motion zeroed.
+// It is always zero: SGP4 models drag through bstar alone and never reads nddot, which the TLE carries
+// for the older SGP model. sat may have come from a TLE or from elements, such as by ElementsToSat.
+func CompareNddotEffect(sat Satellite, t time.Time) (diffKm float64, err error) {
+	// sgp4init works in the internal units the copy already holds, but replaces the mean motion with its
+	// Brouwer value, so start it again from the TLE's
+	zeroed := sat
+	zeroed.nddot = 0
+	zeroed.no = sat.noKozai
+	opsmode := sat.operationmode
+	sgp4init(&opsmode, sat.jdsatepoch-2433281.5, &zeroed)
+
+	nominal, _, err := propagateTime(sat, t)
+	if err != nil {
+		return 0, err
+	}
+	withoutNddot, _, err := propagateTime(zeroed, t)
+	if err != nil {
+		return 0, err
+	}
+	return nominal.sub(withoutNddot).norm(), nil
+}
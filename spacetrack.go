@@ -110,6 +110,7 @@ func (s *Spacetrack) GetTLE(catid uint64, ts time.Time, gravConst Gravity) (Sate
 
 	}
 	sat := TLEToSat(sats[0].Line1, sats[0].Line2, gravConst)
+	sat.Name = sats[0].Name
 	return sat, nil
 }
 
@@ -0,0 +1,229 @@
+package satellite
+
+import (
+	"math"
+	"time"
+)
+
+const astronomicalUnitKm float64 = 149597870.7
+
+// Calculates the geocentric position of the sun in Earth Centered Inertial coordinates(km) for the given julian date.
+// Accurate to about 0.01 degrees between 1950 and 2050.
+// Reference: The Astronomical Almanac, page C5.
+func sunPosition(jday float64) (sun Vector3) {
+	n := jday - 2451545.0
+	meanLong := math.Mod(280.460+0.9856474*n, 360.0)
+	meanAnom := math.Mod(357.528+0.9856003*n, 360.0) * DEG2RAD
+
+	eclLong := (meanLong + 1.915*math.Sin(meanAnom) + 0.020*math.Sin(2*meanAnom)) * DEG2RAD
+	obliquity := (23.439 - 0.0000004*n) * DEG2RAD
+	r := (1.00014 - 0.01671*math.Cos(meanAnom) - 0.00014*math.Cos(2*meanAnom)) * astronomicalUnitKm
+
+	sun.X = r * math.Cos(eclLong)
+	sun.Y = r * math.Cos(obliquity) * math.Sin(eclLong)
+	sun.Z = r * math.Sin(obliquity) * math.Sin(eclLong)
+	return
+}
+
+// SunPosition returns the geocentric position of the sun in Earth Centered Inertial coordinates(km) at
+// time t, from a low precision ephemeris good to about 0.01 degrees between 1950 and 2050
+func SunPosition(t time.Time) Vector3 {
+	return sunPosition(jdayFromTime(t))
+}
+
+// Returns the geometric elevation of the sun in radians above the horizon of a ground location given in radians
+func solarElevation(loc LatLong, jday float64) float64 {
+	return ECIToLookAngles(sunPosition(jday), loc, 0, jday).El
+}
+
+// TerminatorCrossing returns the times between start and end at which the day/night boundary
+// passes over target (latitude and longitude in radians), i.e. when the geometric elevation of
+// the sun's center crosses the target's horizon. Refraction and the solar disc radius are ignored,
+// so the times differ by a few minutes from published sunrise and sunset.
+//
+// Crossings alternate between sunrise and sunset. The search samples every step and refines each
+// crossing to within a millisecond; step must be shorter than the shortest day or night at target.
+func TerminatorCrossing(target LatLong, start, end time.Time, step time.Duration) ([]time.Time, error) {
+	_, transitions, err := findTransitions(start, end, step, func(t time.Time) (bool, error) {
+		return solarElevation(target, jdayFromTime(t)) > 0, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	crossings := make([]time.Time, len(transitions))
+	for i, tr := range transitions {
+		crossings[i] = tr.t
+	}
+	return crossings, nil
+}
+
+// Returns the angle between the orbit plane described by pos and vel and the direction to the sun
+func betaAngle(pos, vel, sun Vector3) float64 {
+	return math.Pi/2 - angleBetween(OrbitNormal(pos, vel), sun)
+}
+
+// BetaAngle returns the solar beta angle in radians at time t: the angle between the
+// satellite's orbit plane and the direction to the sun. It is positive when the sun lies
+// on the side of the plane the orbit's angular momentum vector points to.
+func BetaAngle(sat Satellite, t time.Time) (float64, error) {
+	pos, vel, err := propagateTime(sat, t)
+	if err != nil {
+		return 0, err
+	}
+	return betaAngle(pos, vel, sunPosition(jdayFromTime(t))), nil
+}
+
+// BetaAngleSeries returns the solar beta angle in radians at each step from start to end inclusive.
+//
+// Over weeks the series varies slowly and roughly sinusoidally, driven by the nodal regression
+// of the orbit plane and the sun's annual motion along the ecliptic. Peaks in |beta| mark
+// full-sun periods when the satellite stops entering the Earth's shadow, while values near zero
+// mark the longest eclipses of the season.
+func BetaAngleSeries(sat Satellite, start, end time.Time, step time.Duration) ([]float64, []time.Time, error) {
+	positions, velocities, times, err := propagateRange(sat, start, end, step)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	betas := make([]float64, len(times))
+	for i, t := range times {
+		betas[i] = betaAngle(positions[i], velocities[i], sunPosition(jdayFromTime(t)))
+	}
+
+	return betas, times, nil
+}
+
+// Returns the angle in radians between the sunlight reflected off a flat mirror at satPos with the given
+// surface normal and the direction from satPos to obsPos. The mirror reflects from both faces.
+func glintAngle(satPos, sunPos, obsPos, normal Vector3) float64 {
+	n := normal.unit()
+	incoming := satPos.sub(sunPos).unit()
+	reflected := incoming.sub(n.scale(2 * incoming.dot(n)))
+	return angleBetween(reflected, obsPos.sub(satPos))
+}
+
+// SolarGlintAngle returns the angle in radians between the direction of sunlight reflected off a flat
+// nadir-facing surface on the satellite and the direction from the satellite to obs, placed on the
+// WGS84 ellipsoid, at time t. A flare is seen when the angle approaches zero; how close it must get
+// depends on the surface's flatness. Use SolarGlintAngleWithNormal for surfaces with another
+// orientation.
+//
+// The surface is treated as a perfect two-sided mirror and the sun as a point, and neither the Earth's
+// shadow nor the observer's horizon is checked, so callers should separately confirm the satellite is
+// sunlit and above the horizon.
+func SolarGlintAngle(sat Satellite, obs Observer, t time.Time) (float64, error) {
+	pos, _, err := propagateTime(sat, t)
+	if err != nil {
+		return 0, err
+	}
+	return solarGlintAngle(pos, obs, jdayFromTime(t), pos.scale(-1)), nil
+}
+
+// SolarGlintAngleWithNormal is SolarGlintAngle for a surface whose normal is given in ECI coordinates at time t
+func SolarGlintAngleWithNormal(sat Satellite, obs Observer, t time.Time, normal Vector3) (float64, error) {
+	pos, _, err := propagateTime(sat, t)
+	if err != nil {
+		return 0, err
+	}
+	return solarGlintAngle(pos, obs, jdayFromTime(t), normal), nil
+}
+
+// Returns glintAngle for a satellite at pos seen by obs at the given julian date
+func solarGlintAngle(pos Vector3, obs Observer, jday float64, normal Vector3) float64 {
+	return glintAngle(pos, sunPosition(jday), obs.eci(jday), normal)
+}
+
+// SunReferencedPosition returns the satellite's geocentric position in km at time t in a frame whose x
+// axis points from the Earth's center toward the sun, whose z axis is the celestial north pole made
+// perpendicular to x, and whose y axis completes the right handed set, pointing roughly toward dusk.
+// A positive x means the satellite is on the Earth's day side; y and z locate it around the Earth-sun line.
+func SunReferencedPosition(sat Satellite, t time.Time) (Vector3, error) {
+	pos, _, err := propagateTime(sat, t)
+	if err != nil {
+		return Vector3{}, err
+	}
+	x := sunPosition(jdayFromTime(t)).unit()
+	z := Vector3{Z: 1}.sub(x.scale(x.Z)).unit()
+	y := z.cross(x)
+	return Vector3{X: pos.dot(x), Y: pos.dot(y), Z: pos.dot(z)}, nil
+}
+
+// Longest NextTerminatorCrossing searches ahead, enough for the subpoint of a geostationary satellite
+const terminatorSearchHorizon = 24 * time.Hour
+
+// NextTerminatorCrossing returns the first time after after at which the satellite's subpoint crosses
+// the day/night boundary, where the geometric elevation of the sun's center is zero, along with the
+// subpoint in radians at that time. The elevation is sampled 72 times per nodal period and the crossing
+// refined by bisection to within a millisecond. It returns ErrNotReachedInHorizon if the subpoint stays
+// in daylight or darkness for a day, as it can over polar summer or winter.
+func NextTerminatorCrossing(sat Satellite, after time.Time) (time.Time, LatLong, error) {
+	sunlit := func(t time.Time) (bool, error) {
+		ll, _, err := subpoint(sat, t)
+		return solarElevation(ll, jdayFromTime(t)) > 0, err
+	}
+
+	step := sat.NodalPeriod() / 72
+	end := after.Add(terminatorSearchHorizon)
+	initial, err := sunlit(after)
+	if err != nil {
+		return time.Time{}, LatLong{}, err
+	}
+	for prev, t := after, after.Add(step); !prev.Equal(end); prev, t = t, t.Add(step) {
+		if t.After(end) {
+			t = end
+		}
+		lit, err := sunlit(t)
+		if err != nil {
+			return time.Time{}, LatLong{}, err
+		}
+		if lit != initial {
+			crossing, err := refineTransition(prev, t, initial, sunlit)
+			if err != nil {
+				return time.Time{}, LatLong{}, err
+			}
+			ll, _, err := subpoint(sat, crossing)
+			return crossing, ll, err
+		}
+	}
+	return time.Time{}, LatLong{}, ErrNotReachedInHorizon
+}
+
+// ApparentMagnitude estimates the visual magnitude of the satellite at time t as seen from an observer
+// at observer (geodetic latitude and longitude in radians) and altKm above the WGS84 ellipsoid.
+// intrinsicMag is the satellite's standard magnitude at a range of 1000 km and a phase angle of 90
+// degrees, half of it lit as seen from the observer. It is scaled by the inverse square of the range
+// and by the phase function of a diffuse sphere, sin φ + (π − φ) cos φ, with φ the angle at the
+// satellite between the sun and the observer, so a fully lit satellite is about 1.2 magnitudes
+// brighter than half lit. Lower magnitudes are brighter.
+//
+// visible is false when the satellite is below the horizon or in the Earth's umbra; the magnitude is
+// still returned then. The observer's own daylight isn't considered.
+func ApparentMagnitude(sat *Satellite, intrinsicMag float64, observer LatLong, altKm float64, t time.Time) (mag float64, visible bool, err error) {
+	if sat.init == "" {
+		return 0, false, ErrNotInitialized
+	}
+	pos, _, err := PropagateAtChecked(sat, t)
+	if err != nil {
+		return 0, false, err
+	}
+	jday := jdayFromTime(t)
+	sun := SunPosition(t)
+	obsPos := geodeticECI(observer, altKm, jday)
+	look := topocentricLookAngles(pos, obsPos, observer, jday)
+
+	mag = scaleMagnitude(intrinsicMag, look.Rg, angleBetween(sun.sub(pos), obsPos.sub(pos)))
+	visible = look.El > 0 && !inShadow(pos, sun, sat.whichconst.radiusearthkm)
+	return mag, visible, nil
+}
+
+// Smallest value the phase function of ApparentMagnitude is taken to have. It vanishes, or rounds to just
+// below zero, as the phase angle reaches pi with the satellite's unlit face toward the observer; the floor
+// keeps the magnitude finite there, 15 magnitudes fainter than half lit.
+const minPhaseFunction = 1e-6
+
+// Scales a standard magnitude at 1000 km and 90 degrees phase to rangeKm and the phase angle in radians
+func scaleMagnitude(intrinsicMag, rangeKm, phase float64) float64 {
+	phaseFunction := math.Max(minPhaseFunction, math.Sin(phase)+(math.Pi-phase)*math.Cos(phase))
+	return intrinsicMag + 5*math.Log10(rangeKm/1000) - 2.5*math.Log10(phaseFunction)
+}
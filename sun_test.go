@@ -0,0 +1,201 @@
+package satellite
+
+import (
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("sun", func() {
+	iss := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+	epoch := time.Date(2008, 9, 20, 12, 25, 40, 0, time.UTC)
+
+	Describe("sunPosition", func() {
+		It("should be about one astronomical unit away", func() {
+			r := sunPosition(JDay(2008, 9, 20, 12, 0, 0)).norm()
+			Expect(r / astronomicalUnitKm).To(BeNumerically("~", 1.0, 0.02))
+		})
+
+		It("should reach the obliquity of the ecliptic at the june solstice", func() {
+			sun := sunPosition(JDay(2008, 6, 21, 0, 0, 0))
+			dec := math.Asin(sun.Z/sun.norm()) * RAD2DEG
+			Expect(dec).To(BeNumerically("~", 23.44, 0.05))
+		})
+	})
+
+	Describe("TerminatorCrossing", func() {
+		It("should find sunrise and sunset at greenwich", func() {
+			greenwich := LatLong{Latitude: 51.4769 * DEG2RAD, Longitude: 0}
+			day := time.Date(2008, 9, 20, 0, 0, 0, 0, time.UTC)
+			crossings, err := TerminatorCrossing(greenwich, day, day.Add(24*time.Hour), 10*time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(crossings).To(HaveLen(2))
+			Expect(crossings[0]).To(BeTemporally("~", day.Add(5*time.Hour+50*time.Minute), 10*time.Minute))
+			Expect(crossings[1]).To(BeTemporally("~", day.Add(18*time.Hour+5*time.Minute), 10*time.Minute))
+		})
+	})
+
+	Describe("BetaAngleSeries", func() {
+		It("should return one beta angle per step inclusive of the end time", func() {
+			betas, times, err := BetaAngleSeries(iss, epoch, epoch.Add(24*time.Hour), time.Hour)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(betas).To(HaveLen(25))
+			Expect(times).To(HaveLen(25))
+			Expect(times[24]).To(Equal(epoch.Add(24 * time.Hour)))
+			for _, beta := range betas {
+				Expect(math.Abs(beta)).To(BeNumerically("<=", math.Pi/2))
+			}
+		})
+
+		It("should match the single time beta angle", func() {
+			betas, _, err := BetaAngleSeries(iss, epoch, epoch.Add(time.Hour), time.Hour)
+			Expect(err).NotTo(HaveOccurred())
+			beta, err := BetaAngle(iss, epoch.Add(time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(betas[1]).To(Equal(beta))
+		})
+
+		It("should reject a non-positive step", func() {
+			_, _, err := BetaAngleSeries(iss, epoch, epoch.Add(time.Hour), 0)
+			Expect(err).To(Equal(ErrInvalidStep))
+		})
+	})
+	Describe("SolarGlintAngle", func() {
+		It("should be zero when the observer sits on the specular ray", func() {
+			sat := Vector3{X: 7000}
+			sun := sat.add(Vector3{X: -1, Y: 1}.scale(astronomicalUnitKm))
+			obs := sat.add(Vector3{X: -1, Y: -1}.scale(1000))
+			Expect(glintAngle(sat, sun, obs, Vector3{X: -1})).To(BeNumerically("~", 0, 1e-9))
+			Expect(glintAngle(sat, sun, obs, Vector3{X: 1})).To(BeNumerically("~", 0, 1e-9))
+			Expect(glintAngle(sat, sun, obs, Vector3{Y: 1})).To(BeNumerically("~", math.Pi, 1e-9))
+		})
+
+		It("should default to a nadir facing surface", func() {
+			obs := Observer{Coords: LatLong{Latitude: 40 * DEG2RAD, Longitude: -75 * DEG2RAD}}
+			pos, _, err := propagateTime(iss, epoch)
+			Expect(err).NotTo(HaveOccurred())
+
+			angle, err := SolarGlintAngle(iss, obs, epoch)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(SolarGlintAngleWithNormal(iss, obs, epoch, pos.scale(-1))).To(Equal(angle))
+			Expect(angle).To(BeNumerically(">=", 0))
+			Expect(angle).To(BeNumerically("<=", math.Pi))
+		})
+
+		It("should reflect towards the station's position on the ellipsoid", func() {
+			obs := Observer{Coords: LatLong{Latitude: 62 * DEG2RAD, Longitude: 25 * DEG2RAD}, Altitude: 0.2}
+			normal := Vector3{X: 0.3, Y: -0.5, Z: 0.8}
+			for t := epoch; t.Before(epoch.Add(3 * time.Hour)); t = t.Add(17 * time.Minute) {
+				angle, err := SolarGlintAngleWithNormal(iss, obs, t, normal)
+				Expect(err).NotTo(HaveOccurred())
+
+				pos, _ := PropagateAt(&iss, t)
+				jday := jdayFromTime(t)
+				station := ECEFToECI(LLAToECEF(obs.Coords, obs.Altitude), ThetaG_JD(jday))
+				Expect(angle).To(BeNumerically("~", glintAngle(pos, sunPosition(jday), station, normal), 1e-12))
+			}
+		})
+	})
+	Describe("SunReferencedPosition", func() {
+		It("should preserve the distance and put the sun along x", func() {
+			pos, _, err := propagateTime(iss, epoch)
+			Expect(err).NotTo(HaveOccurred())
+			rel, err := SunReferencedPosition(iss, epoch)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rel.norm()).To(BeNumerically("~", pos.norm(), 1e-6))
+
+			sun := sunPosition(jdayFromTime(epoch))
+			Expect(rel.X).To(BeNumerically("~", pos.dot(sun.unit()), 1e-6))
+		})
+
+		It("should place an eclipsed satellite on the night side", func() {
+			for m := 0; m < 92; m += 10 {
+				t := epoch.Add(time.Duration(m) * time.Minute)
+				rel, err := SunReferencedPosition(iss, t)
+				Expect(err).NotTo(HaveOccurred())
+				shadowed, err := iss.shadowed(t)
+				Expect(err).NotTo(HaveOccurred())
+				if shadowed {
+					Expect(rel.X).To(BeNumerically("<", 0))
+				}
+			}
+		})
+	})
+	Describe("NextTerminatorCrossing", func() {
+		It("should find where the subpoint's solar elevation crosses zero", func() {
+			first, ll, err := NextTerminatorCrossing(iss, epoch)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(first.After(epoch)).To(BeTrue())
+			Expect(first.Sub(epoch)).To(BeNumerically("<", iss.NodalPeriod()))
+			Expect(solarElevation(ll, jdayFromTime(first))).To(BeNumerically("~", 0, 1e-4))
+
+			sub, _, err := subpoint(iss, first)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ll).To(Equal(sub))
+
+			// The next crossing is the opposite transit, about half an orbit later
+			second, _, err := NextTerminatorCrossing(iss, first.Add(time.Second))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(second.Sub(first)).To(BeNumerically("~", iss.NodalPeriod()/2, 15*time.Minute))
+
+			elevationBefore := func(t time.Time) float64 {
+				ll, _, err := subpoint(iss, t.Add(-time.Minute))
+				Expect(err).NotTo(HaveOccurred())
+				return solarElevation(ll, jdayFromTime(t.Add(-time.Minute)))
+			}
+			Expect(math.Signbit(elevationBefore(first))).NotTo(Equal(math.Signbit(elevationBefore(second))))
+		})
+	})
+	Describe("SunPosition", func() {
+		It("should be an astronomical unit away and north of the equator in June", func() {
+			sun := SunPosition(time.Date(2020, 6, 21, 0, 0, 0, 0, time.UTC))
+			Expect(sun.norm() / astronomicalUnitKm).To(BeNumerically("~", 1.016, 0.001))
+			Expect(math.Asin(sun.Z/sun.norm()) * RAD2DEG).To(BeNumerically("~", 23.44, 0.05))
+		})
+	})
+
+	Describe("ApparentMagnitude", func() {
+		observer := LatLong{Latitude: 45 * DEG2RAD, Longitude: 10 * DEG2RAD}
+
+		It("should scale the standard magnitude by range and phase", func() {
+			Expect(scaleMagnitude(-1.8, 1000, math.Pi/2)).To(BeNumerically("~", -1.8, 1e-12))
+			Expect(scaleMagnitude(-1.8, 10000, math.Pi/2)).To(BeNumerically("~", 3.2, 1e-12))
+			Expect(scaleMagnitude(-1.8, 1000, 0)).To(BeNumerically("~", -1.8-2.5*math.Log10(math.Pi), 1e-12))
+			Expect(scaleMagnitude(-1.8, 1000, 2)).To(BeNumerically(">", -1.8))
+		})
+
+		It("should stay finite with the unlit face toward the observer", func() {
+			for _, phase := range []float64{math.Pi, math.Pi - 1e-9, math.Nextafter(math.Pi, 0)} {
+				mag := scaleMagnitude(-1.8, 1000, phase)
+				Expect(math.IsNaN(mag) || math.IsInf(mag, 0)).To(BeFalse())
+				Expect(mag).To(BeNumerically("~", -1.8+15, 1e-9))
+			}
+			Expect(scaleMagnitude(-1.8, 1000, 3)).To(BeNumerically("<", -1.8+15))
+		})
+
+		It("should only call the satellite visible when sunlit and above the horizon", func() {
+			var visibleSamples int
+			for t := epoch; t.Before(epoch.Add(2 * 24 * time.Hour)); t = t.Add(30 * time.Second) {
+				mag, visible, err := ApparentMagnitude(&iss, -1.8, observer, 0, t)
+				Expect(err).NotTo(HaveOccurred())
+
+				look, _ := ObserverLookAngles(&iss, observer, 0, t)
+				sunlit, _ := IsSunlit(&iss, t)
+				Expect(visible).To(Equal(look.El > 0 && sunlit))
+				if visible {
+					visibleSamples++
+					Expect(mag).To(BeNumerically(">", -5))
+					Expect(mag).To(BeNumerically("<", 6))
+				}
+			}
+			Expect(visibleSamples).To(BeNumerically(">", 0))
+		})
+
+		It("should report satellites that can't be propagated", func() {
+			_, _, err := ApparentMagnitude(&Satellite{}, -1.8, observer, 0, epoch)
+			Expect(err).To(Equal(ErrNotInitialized))
+		})
+	})
+})
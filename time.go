@@ -0,0 +1,55 @@
+package satellite
+
+import (
+	"time"
+)
+
+// UT1MinusUTC is the offset UT1 − UTC (DUT1, published in IERS Bulletin A) applied to every UTC julian
+// date before a sidereal time is computed from it, and so to every conversion between inertial and Earth
+// fixed coordinates. It defaults to zero, the standard SGP4 assumption that UT1 equals UTC, which
+// misplaces Earth fixed positions by up to 0.9 s of Earth rotation, about 400 m at the equator.
+// Set it before using the package; it isn't safe to change while other goroutines are propagating.
+var UT1MinusUTC time.Duration
+
+// Converts a UTC julian date into UT1 using UT1MinusUTC
+func ut1FromUTC(jdutc float64) float64 {
+	return jdutc + UT1MinusUTC.Seconds()/86400.0
+}
+
+// Calc julian date for the given time, interpreted as UTC and carrying fractional seconds
+func jdayFromTime(t time.Time) float64 {
+	t = t.UTC()
+	jday := JDay(t.Year(), int(t.Month()), t.Day(), t.Hour(), t.Minute(), t.Second())
+	return jday + float64(t.Nanosecond())/(86400.0*1e9)
+}
+
+// Julian date of the J2000 epoch, 2000 January 1 12:00 UTC
+const j2000JulianDate = 2451545.0
+
+// Converts a UTC julian date into a time, rounded to the microsecond the float64 date can resolve
+func timeFromJday(jday float64) time.Time {
+	j2000 := time.Date(2000, time.January, 1, 12, 0, 0, 0, time.UTC)
+	offset := time.Duration((jday - j2000JulianDate) * 86400 * float64(time.Second))
+	return j2000.Add(offset).Round(time.Microsecond)
+}
+
+// Returns the times from start to end spaced by step. The end time is always included.
+func sampleTimes(start, end time.Time, step time.Duration) ([]time.Time, error) {
+	if step <= 0 {
+		return nil, ErrInvalidStep
+	}
+	if end.Before(start) {
+		return nil, ErrInvalidTimeRange
+	}
+
+	n := int(end.Sub(start)/step) + 1
+	times := make([]time.Time, 0, n+1)
+	for i := 0; i < n; i++ {
+		times = append(times, start.Add(time.Duration(i)*step))
+	}
+	if times[len(times)-1].Before(end) {
+		times = append(times, end)
+	}
+
+	return times, nil
+}
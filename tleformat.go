@@ -0,0 +1,103 @@
+package satellite
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ToTLE formats the satellite's mean elements as the two lines of a TLE, with the checksum in column 69
+// of each. Satellites initialized by sgp4init have their elements converted back from radians and the
+// internal per-minute units. Round tripping a parsed TLE reproduces its lines, apart from a possible
+// difference in the sign of a zero exponent. It returns an error wrapping ErrInvalidElements if a value
+// doesn't fit its fixed columns, and ErrInvalidCatalogNumber for a catalog number above 339999.
+func (sat *Satellite) ToTLE() (line1, line2 string, err error) {
+	satnum, err := EncodeAlpha5(sat.satnum)
+	if err != nil {
+		return "", "", err
+	}
+	classification := sat.classification
+	if classification == 0 {
+		classification = 'U'
+	}
+
+	ndot, nddot := sat.ndot, sat.nddot
+	if sat.init != "" {
+		ndot *= XPDOTP * 1440.0
+		nddot *= XPDOTP * 1440.0 * 1440.0
+	}
+	ndotField, err := formatTLEDecimal(ndot)
+	if err != nil {
+		return "", "", errors.Wrap(err, "first derivative of mean motion")
+	}
+	nddotField, err := formatTLEExponent(nddot)
+	if err != nil {
+		return "", "", errors.Wrap(err, "second derivative of mean motion")
+	}
+	bstarField, err := formatTLEExponent(sat.bstar)
+	if err != nil {
+		return "", "", errors.Wrap(err, "bstar")
+	}
+
+	ecc := math.Round(sat.ecco * 1e7)
+	if ecc < 0 || ecc >= 1e7 {
+		return "", "", errors.Wrapf(ErrInvalidElements, "eccentricity %g", sat.ecco)
+	}
+	if sat.epochyr < 0 || sat.epochyr > 99 || sat.epochdays < 1 || sat.epochdays >= 367 {
+		return "", "", errors.Wrapf(ErrInvalidElements, "epoch %d %g", sat.epochyr, sat.epochdays)
+	}
+
+	line1 = fmt.Sprintf("1 %s%c %-8s %02d%012.8f %s %s %s %d %4d",
+		satnum, classification, sat.intldesg, sat.epochyr, sat.epochdays, ndotField, nddotField, bstarField, sat.ephtype, sat.elnum%10000)
+	line2 = fmt.Sprintf("2 %s %8.4f %8.4f %07.0f %8.4f %8.4f %11.8f%5d",
+		satnum, sat.Inclination(), sat.RAAN(), ecc, sat.ArgPerigee(), sat.MeanAnomaly(), sat.MeanMotion(), sat.revnum%100000)
+	if len(line1) != tleChecksumColumn || len(line2) != tleChecksumColumn {
+		return "", "", errors.Wrap(ErrInvalidElements, "elements don't fit the TLE columns")
+	}
+	return line1 + fmt.Sprint(tleChecksum(line1)), line2 + fmt.Sprint(tleChecksum(line2)), nil
+}
+
+// Formats a value below one in magnitude as the sign and implied leading zero decimal of TLE columns 34-43
+func formatTLEDecimal(x float64) (string, error) {
+	if math.Abs(x) >= 1 {
+		return "", errors.Wrapf(ErrInvalidElements, "%g doesn't fit", x)
+	}
+	s := strings.TrimPrefix(fmt.Sprintf("%.8f", math.Abs(x)), "0")
+	if s == "1.00000000" {
+		return "", errors.Wrapf(ErrInvalidElements, "%g doesn't fit", x)
+	}
+	return tleSign(x) + s, nil
+}
+
+// Formats a value in the implied decimal exponential notation of TLE columns 45-52 and 54-61: a sign,
+// five digits of mantissa after an implied "0." and a signed single digit exponent, as in -11606-4
+func formatTLEExponent(x float64) (string, error) {
+	if x == 0 {
+		return " 00000-0", nil
+	}
+	a := math.Abs(x)
+	exp := int(math.Floor(math.Log10(a))) + 1
+	mantissa := math.Round(a / math.Pow(10, float64(exp)) * 1e5)
+	if mantissa >= 1e5 {
+		mantissa /= 10
+		exp++
+	}
+	if exp < -9 || exp > 9 {
+		return "", errors.Wrapf(ErrInvalidElements, "%g doesn't fit", x)
+	}
+	expSign := "+"
+	if exp < 0 {
+		expSign, exp = "-", -exp
+	}
+	return fmt.Sprintf("%s%05.0f%s%d", tleSign(x), mantissa, expSign, exp), nil
+}
+
+// Returns the sign column of a TLE field, blank for positive values
+func tleSign(x float64) string {
+	if x < 0 {
+		return "-"
+	}
+	return " "
+}
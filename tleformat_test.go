@@ -0,0 +1,73 @@
+package satellite
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/pkg/errors"
+)
+
+var _ = Describe("ToTLE", func() {
+	tles := [][2]string{
+		{"1 00005U 58002B   00179.78495062  .00000023  00000-0  28098-4 0  4753", "2 00005  34.2682 348.7242 1859667 331.7664  19.3264 10.82419157413667"},
+		{"1 04632U 70093B   04031.91070959 -.00000084  00000-0  10000-3 0  9955", "2 04632  11.4628 273.1101 1450506 207.6000 143.9350  1.20231981 44145"},
+		{"1 06251U 62025E   06176.82412014  .00008885  00000-0  12808-3 0  3985", "2 06251  58.0579  54.0425 0030035 139.1568 221.1854 15.56387291  6774"},
+		{"1 23599U 95029B   06171.76535463  .00085586  12891-6  12956-2 0  2905", "2 23599   6.9327   0.2849 5782022 274.4436  25.2425  4.47796565123555"},
+		{"1 24208U 96044A   06177.04061740 -.00000094  00000-0  10000-3 0  1600", "2 24208   3.8536  80.0121 0026640 311.0977  48.3000  1.00778054 36119"},
+		{"1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537"},
+		{"1 88888U          80275.98708465  .00073094  13844-3  66816-4 0    8", "2 88888  72.8435 115.9689 0086731  52.6988 110.5714 16.05824518  105"},
+	}
+
+	It("should reproduce parsed and initialized TLEs", func() {
+		for _, tle := range tles {
+			parsed := ParseTLE(tle[0], tle[1], GravityWGS72)
+			initialized := TLEToSat(tle[0], tle[1], GravityWGS72)
+			for _, sat := range []*Satellite{&parsed, &initialized} {
+				line1, line2, err := sat.ToTLE()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(line1[:68]).To(Equal(tle[0][:68]))
+				Expect(line2[:68]).To(Equal(tle[1][:68]))
+
+				for _, line := range []string{line1, line2} {
+					ok, err := VerifyChecksum(line)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(ok).To(BeTrue())
+				}
+			}
+		}
+	})
+
+	It("should format elements built without a TLE", func() {
+		sat, err := ElementsToSat(Elements{
+			SatNum:       275678,
+			Epoch:        time.Date(2024, 3, 1, 6, 0, 0, 0, time.UTC),
+			Inclination:  97.5,
+			RAAN:         10.25,
+			Eccentricity: 0.0012345,
+			ArgPerigee:   90,
+			MeanAnomaly:  270,
+			MeanMotion:   15.2,
+			Bstar:        0.00012345,
+		}, GravityWGS72)
+		Expect(err).NotTo(HaveOccurred())
+
+		line1, line2, err := sat.ToTLE()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(line1).To(HavePrefix("1 T5678U          24061.25000000  .00000000  00000-0  12345-3 0    0"))
+		Expect(line2).To(HavePrefix("2 T5678  97.5000  10.2500 0012345  90.0000 270.0000 15.20000000    0"))
+
+		reparsed, err := TLEToSatV2(line1, line2, GravityWGS72)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reparsed.SatNum()).To(Equal(int64(275678)))
+		Expect(reparsed.Bstar()).To(BeNumerically("~", 0.00012345, 1e-12))
+	})
+
+	It("should reject values that don't fit their columns", func() {
+		sat := ParseTLE(tles[5][0], tles[5][1], GravityWGS72)
+		sat.bstar = 1e12
+		_, _, err := sat.ToTLE()
+		Expect(errors.Cause(err)).To(Equal(ErrInvalidElements))
+	})
+})
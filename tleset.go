@@ -0,0 +1,121 @@
+package satellite
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ParseTLE3 converts a three line element set, whose first line is the satellite's common name, into
+// an initialized Satellite like TLEToSatV2. The name is stored with surrounding whitespace and the "0 "
+// prefix of Space-Track's 3LE format removed.
+func ParseTLE3(name, line1, line2 string, gravConst Gravity) (*Satellite, error) {
+	sat, err := TLEToSatV2(line1, line2, gravConst)
+	if err != nil {
+		return nil, err
+	}
+	sat.Name = trimTLEName(name)
+	return &sat, nil
+}
+
+// Removes surrounding whitespace and the line number Space-Track puts before names
+func trimTLEName(name string) string {
+	name = strings.TrimSpace(name)
+	if strings.HasPrefix(name, "0 ") {
+		name = strings.TrimSpace(name[2:])
+	}
+	return name
+}
+
+// TLESetError describes a record of an element set file that couldn't be parsed
+type TLESetError struct {
+	Line int // Line number, from 1, on which the record starts
+	Err  error
+}
+
+func (e *TLESetError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// Cause returns the underlying error, so errors.Cause finds sentinels such as ErrInvalidTLE
+func (e *TLESetError) Cause() error {
+	return e.Err
+}
+
+// TLESetErrors lists every record ParseTLESet couldn't parse
+type TLESetErrors []*TLESetError
+
+func (e TLESetErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// ParseTLESet reads a file of element sets, such as those from CelesTrak and Space-Track, and returns
+// an initialized Satellite for each. Records may have a name line before their two element lines or
+// not, and blank lines between them are ignored. Records that fail to parse are skipped and reported
+// together as TLESetErrors, so the satellites parsed from the rest of the file are still returned.
+// Errors reading r are returned as is.
+func ParseTLESet(r io.Reader, gravConst Gravity) ([]*Satellite, error) {
+	var sats []*Satellite
+	var errs TLESetErrors
+
+	// The record being assembled
+	var name, line1 string
+	var start int
+	fail := func(err error) {
+		errs = append(errs, &TLESetError{Line: start, Err: err})
+		name, line1, start = "", "", 0
+	}
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "1 "):
+			if line1 != "" {
+				fail(errors.Wrap(ErrInvalidTLE, "line 1 without line 2"))
+			}
+			if start == 0 {
+				start = n
+			}
+			line1 = line
+		case strings.HasPrefix(line, "2 "):
+			if line1 == "" {
+				start = n
+				fail(errors.Wrap(ErrInvalidTLE, "line 2 without line 1"))
+				continue
+			}
+			sat, err := ParseTLE3(name, line1, line, gravConst)
+			if err != nil {
+				fail(err)
+				continue
+			}
+			sats = append(sats, sat)
+			name, line1, start = "", "", 0
+		default:
+			if line1 != "" {
+				fail(errors.Wrap(ErrInvalidTLE, "line 1 without line 2"))
+			}
+			name, start = line, n
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return sats, err
+	}
+	if line1 != "" {
+		fail(errors.Wrap(ErrInvalidTLE, "line 1 without line 2"))
+	}
+
+	if len(errs) > 0 {
+		return sats, errs
+	}
+	return sats, nil
+}
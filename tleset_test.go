@@ -0,0 +1,64 @@
+package satellite
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/pkg/errors"
+)
+
+var _ = Describe("TLE sets", func() {
+	line1 := "1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927"
+	line2 := "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537"
+	geo1 := "1 24208U 96044A   06177.04061740 -.00000094  00000-0  10000-3 0  1600"
+	geo2 := "2 24208   3.8536  80.0121 0026640 311.0977  48.3000  1.00778054 36119"
+
+	Describe("ParseTLE3", func() {
+		It("should store the trimmed name on an initialized satellite", func() {
+			sat, err := ParseTLE3("ISS (ZARYA)             \r", line1, line2, "wgs72")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sat.Name).To(Equal("ISS (ZARYA)"))
+
+			want := TLEToSat(line1, line2, "wgs72")
+			want.Name = sat.Name
+			Expect(*sat).To(Equal(want))
+		})
+
+		It("should drop the line number of Space-Track's 3LE format", func() {
+			sat, err := ParseTLE3("0 ISS (ZARYA)", line1, line2, "wgs72")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sat.Name).To(Equal("ISS (ZARYA)"))
+		})
+	})
+
+	Describe("ParseTLESet", func() {
+		It("should read named and unnamed records separated by blank lines", func() {
+			file := "ISS (ZARYA)\n" + line1 + "\n" + line2 + "\n\n\r\n" + geo1 + "\r\n" + geo2 + "\r\n"
+			sats, err := ParseTLESet(strings.NewReader(file), "wgs72")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sats).To(HaveLen(2))
+			Expect(sats[0].Name).To(Equal("ISS (ZARYA)"))
+			Expect(sats[0].satnum).To(Equal(int64(25544)))
+			Expect(sats[1].Name).To(BeEmpty())
+			Expect(sats[1].satnum).To(Equal(int64(24208)))
+		})
+
+		It("should skip bad records and report them with their line numbers", func() {
+			bad2 := strings.Replace(line2, "15.72", "15.7X", 1)
+			file := "BROKEN\n" + line1 + "\n" + bad2 + "\n" + "ORPHAN\n" + geo1 + "\n\nGEO\n" + geo1 + "\n" + geo2 + "\n"
+			sats, err := ParseTLESet(strings.NewReader(file), "wgs72")
+			Expect(sats).To(HaveLen(1))
+			Expect(sats[0].Name).To(Equal("GEO"))
+
+			var errs TLESetErrors
+			Expect(errors.As(err, &errs)).To(BeTrue())
+			Expect(errs).To(HaveLen(2))
+			Expect(errs[0].Line).To(Equal(1))
+			Expect(errors.Cause(errs[0])).To(Equal(ErrInvalidTLE))
+			Expect(errs[1].Line).To(Equal(4))
+			Expect(err.Error()).To(ContainSubstring("line 4: line 1 without line 2"))
+		})
+	})
+})
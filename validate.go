@@ -0,0 +1,50 @@
+package satellite
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// Radius in km of the Earth's sphere of influence, beyond which an element set can't describe an Earth orbit
+const sphereOfInfluenceKm float64 = 925000.0
+
+var ErrImplausibleOrbit = errors.New("implausible orbit")
+
+// Validate checks that an initialized satellite describes a physically sane orbit. It checks the mean
+// elements, then propagates to epoch and checks that the position lies between the Earth's surface and
+// the edge of its sphere of influence and that the orbit is bound. Failures wrap ErrImplausibleOrbit
+// with a description of the problem. This catches corrupt-but-parseable element sets early.
+func (sat *Satellite) Validate() error {
+	if sat.whichconst.mu == 0 {
+		return errors.Wrap(ErrImplausibleOrbit, "satellite has not been initialized")
+	}
+	if sat.no <= 0 {
+		return errors.Wrapf(ErrImplausibleOrbit, "mean motion %g is not positive", sat.no*XPDOTP)
+	}
+	if sat.ecco < 0 || sat.ecco >= 1 {
+		return errors.Wrapf(ErrImplausibleOrbit, "eccentricity %g is outside [0, 1)", sat.ecco)
+	}
+
+	s := *sat
+	pos, vel := sgp4(&s, 0)
+	if s.Error != 0 {
+		return errors.Wrap(ErrImplausibleOrbit, s.ErrorStr)
+	}
+
+	r, v := pos.norm(), vel.norm()
+	if math.IsNaN(r) || math.IsNaN(v) {
+		return errors.Wrap(ErrImplausibleOrbit, "propagation at epoch produced NaN")
+	}
+	if r < s.whichconst.radiusearthkm {
+		return errors.Wrapf(ErrImplausibleOrbit, "position %.1f km from the Earth's center is below the surface", r)
+	}
+	if r > sphereOfInfluenceKm {
+		return errors.Wrapf(ErrImplausibleOrbit, "position %.1f km from the Earth's center is outside its sphere of influence", r)
+	}
+	if energy := v*v/2 - s.whichconst.mu/r; energy >= 0 {
+		return errors.Wrapf(ErrImplausibleOrbit, "orbit is not bound, specific energy is %g km²/s²", energy)
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package satellite
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/pkg/errors"
+)
+
+var _ = Describe("Validate", func() {
+	It("should accept well formed element sets", func() {
+		for _, sat := range []Satellite{
+			TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72"),
+			TLEToSat("1 04632U 70093B   04031.91070959 -.00000084  00000-0  10000-3 0  9955", "2 04632  11.4628 273.1101 1450506 207.6000 143.9350  1.20231981 44145", "wgs72"),
+		} {
+			Expect(sat.Validate()).To(Succeed())
+		}
+	})
+
+	It("should reject an orbit far outside the earth's sphere of influence", func() {
+		sat := TLEToSat("1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288  0.00500000563537", "wgs72")
+		err := sat.Validate()
+		Expect(err).To(HaveOccurred())
+		Expect(errors.Cause(err)).To(Equal(ErrImplausibleOrbit))
+	})
+
+	It("should reject an uninitialized satellite", func() {
+		Expect(errors.Cause((&Satellite{}).Validate())).To(Equal(ErrImplausibleOrbit))
+	})
+})
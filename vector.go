@@ -0,0 +1,50 @@
+package satellite
+
+import (
+	"math"
+)
+
+// Returns the sum of two vectors
+func (v Vector3) add(u Vector3) Vector3 {
+	return Vector3{X: v.X + u.X, Y: v.Y + u.Y, Z: v.Z + u.Z}
+}
+
+// Returns the difference of two vectors
+func (v Vector3) sub(u Vector3) Vector3 {
+	return Vector3{X: v.X - u.X, Y: v.Y - u.Y, Z: v.Z - u.Z}
+}
+
+// Returns the vector multiplied by a scalar
+func (v Vector3) scale(s float64) Vector3 {
+	return Vector3{X: v.X * s, Y: v.Y * s, Z: v.Z * s}
+}
+
+// Returns the dot product of two vectors
+func (v Vector3) dot(u Vector3) float64 {
+	return v.X*u.X + v.Y*u.Y + v.Z*u.Z
+}
+
+// Returns the cross product of two vectors
+func (v Vector3) cross(u Vector3) Vector3 {
+	return Vector3{
+		X: v.Y*u.Z - v.Z*u.Y,
+		Y: v.Z*u.X - v.X*u.Z,
+		Z: v.X*u.Y - v.Y*u.X,
+	}
+}
+
+// Returns the magnitude of the vector
+func (v Vector3) norm() float64 {
+	return math.Sqrt(v.dot(v))
+}
+
+// Returns the vector scaled to unit length
+func (v Vector3) unit() Vector3 {
+	return v.scale(1.0 / v.norm())
+}
+
+// Returns the angle in radians between two vectors.
+// atan2 is used rather than acos so nearly parallel vectors don't lose precision.
+func angleBetween(v, u Vector3) float64 {
+	return math.Atan2(v.cross(u).norm(), v.dot(u))
+}